@@ -1,9 +1,9 @@
 module github.com/imarsman/timestamp
 
-go 1.18
+go 1.23
 
 require (
-	github.com/JohnCGriffin/overflow v0.0.0-20211019200055-46fa312c352c // indirect
-	github.com/matryer/is v1.4.0 // indirect
-	lab.nexedi.com/kirr/go123 v0.0.0-20220316115630-070bfdbb5d00 // indirect
+	github.com/JohnCGriffin/overflow v0.0.0-20211019200055-46fa312c352c
+	github.com/matryer/is v1.4.0
+	lab.nexedi.com/kirr/go123 v0.0.0-20220316115630-070bfdbb5d00
 )
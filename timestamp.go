@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/JohnCGriffin/overflow"
-	"github.com/imarsman/timestamp/pkg/utility"
 	"github.com/imarsman/timestamp/pkg/xfmt"
 	// gocache "github.com/patrickmn/go-cache"
 	// https://golang.org/pkg/time/tzdata/
@@ -76,7 +75,7 @@ func Int64Overflows(int64s ...int64) (sum int64, ok bool) {
 		}
 	}
 
-	return
+	return sum, false
 }
 
 // DurationOverflows does a list of durations overflow int64?
@@ -88,7 +87,7 @@ func DurationOverflows(durations ...time.Duration) (sum int64, ok bool) {
 		}
 	}
 
-	return
+	return sum, false
 }
 
 func init() {
@@ -202,23 +201,39 @@ func TwoDigitOffset(in int, addPrefix bool) (digits string, err error) {
 		return
 	}
 
+	var buf [3]byte
+	return string(appendTwoDigits(buf[:0], in, addPrefix)), nil
+}
+
+// appendTwoDigits append a value from -99 to 99 as two digits, prefixed with
+// its sign if addPrefix is true. Values outside that range must be checked for
+// by the caller.
+func appendTwoDigits(b []byte, in int, addPrefix bool) []byte {
 	// Figure out prefix based on sign of input and make input always positive
-	var prefix rune = '+'
+	var prefix byte = '+'
 	if in < 0 {
 		prefix = '-'
 		in = -in
 	}
+	if addPrefix == true {
+		b = append(b, prefix)
+	}
 
-	// First rune is the integer part after an integer division
-	// Second rune is the remainder
-	var fr rune = rune('0' + int(in/10))
-	var lr rune = rune('0' + in%10)
+	// First digit is the integer part after an integer division
+	// Second digit is the remainder
+	return append(b, byte('0'+in/10), byte('0'+in%10))
+}
 
-	// Return either the prefixed two characters or non-prefixed
-	if addPrefix == true {
-		return utility.RunesToString(prefix, fr, lr), nil
+// appendOffset append an offset in HHMM or HH:MM format with a sign prefix
+func appendOffset(b []byte, d time.Duration, delimited bool) []byte {
+	offsetH, offsetM := OffsetHM(d)
+
+	b = appendTwoDigits(b, offsetH, true)
+	if delimited == true {
+		b = append(b, ':')
 	}
-	return utility.RunesToString(fr, lr), nil
+
+	return appendTwoDigits(b, offsetM, false)
 }
 
 // OffsetString get an offset in HHMM format based on hours and minutes offset
@@ -230,33 +245,29 @@ func TwoDigitOffset(in int, addPrefix bool) (digits string, err error) {
 // For -5 hours and 30 minutes
 //  -0500
 func locationOffsetString(d time.Duration, delimited bool) (offset string, err error) {
-	offsetH, offsetM := OffsetHM(d)
-
-	xfmt := new(xfmt.Buffer)
-
-	h, err := TwoDigitOffset(offsetH, true)
-	if err != nil {
-		return
-	}
-	xfmt.S(h)
-	if delimited == true {
-		xfmt.C(':')
-	}
-	m, err := TwoDigitOffset(offsetM, false)
-	if err != nil {
+	offsetH, _ := OffsetHM(d)
+	if offsetH > 99 || offsetH < -99 {
+		err = errors.New("Out of range")
 		return
 	}
-	xfmt.S(m)
 
-	offset = BytesToString(xfmt.Bytes()...)
+	var buf [6]byte
+	offset = string(appendOffset(buf[:0], d, delimited))
 
 	return
 }
 
 // RangeOverTimes returns a date range function over start date to end date inclusive.
 // After the end of the range, the range function returns a zero date,
-// date.IsZero() is true. If zones for start and end differ an error will be
-// returned and needs to be checked for before time.IsZero().
+// date.IsZero() is true. If the locations for start and end differ an error
+// will be returned and needs to be checked for before time.IsZero(). Use
+// RangeOverDays to convert the end to the location of the start instead.
+//
+// Days are calendar days in the location of start, so a day across a daylight
+// saving change is 23 or 25 hours and a range from January to July in a zone
+// with daylight saving time works even though the offsets differ. Each time
+// returned is the first instant of its day, which is midnight except on days
+// where a daylight saving change skips midnight.
 //
 // Note that this function has been modified to NOT change the location for the
 // start and end time to UTC. This is in keeping with the avoidance of change to
@@ -288,7 +299,7 @@ func locationOffsetString(d time.Duration, delimited bool) (offset string, err e
 	}
 
 	if err != nil {
-		// handle error due to non-equal UTC offsets
+		// handle error due to differing locations
 	}
 
 	a := make([]string, 0, len(m))
@@ -303,26 +314,36 @@ func locationOffsetString(d time.Duration, delimited bool) (offset string, err e
 	}
 */
 func RangeOverTimes(start, end time.Time) func() (time time.Time, err error) {
-	_, startZone := start.Zone()
-	_, endZone := end.Zone()
+	return RangeOverDays(start, end, false)
+}
 
-	if startZone != endZone {
+// RangeOverDays returns a date range function over start date to end date
+// inclusive as RangeOverTimes does. With convertEnd the end is moved to the
+// location of the start, so the range covers the days of the start's location
+// that the end falls on or after. Without it differing locations are an error.
+//
+// Locations are compared by identity, so two locations loaded separately for
+// the same zone differ.
+func RangeOverDays(start, end time.Time, convertEnd bool) func() (time time.Time, err error) {
+	location := start.Location()
+	if convertEnd {
+		end = end.In(location)
+	} else if end.Location() != location {
 		return func() (time.Time, error) {
-			return time.Time{}, errors.New("Zones for start and end differ")
+			return time.Time{}, errors.New("Locations for start and end differ")
 		}
 	}
 
-	y, m, d := start.Date()
-	start = time.Date(y, m, d, 0, 0, 0, 0, start.Location())
-	y, m, d = end.Date()
-	end = time.Date(y, m, d, 0, 0, 0, 0, end.Location())
+	day, last := DateOf(start), DateOf(end)
 
 	return func() (time.Time, error) {
-		if start.After(end) {
+		if day.After(last) {
 			return time.Time{}, nil
 		}
-		date := start
-		start = start.AddDate(0, 0, 1)
+		// DSTCompatible gives the first instant of a day with no midnight
+		// and never gives an error
+		date, _ := day.In(location, DSTCompatible)
+		day = day.AddDays(1)
 
 		return date, nil
 	}
@@ -335,6 +356,8 @@ func RangeOverTimes(start, end time.Time) func() (time time.Time, err error) {
 // time locations passed into function. It is the responsibility of the caller
 // to set location in keeping with the intended use of the function.
 //
+// Use DateOf for a date that does not depend on a location.
+//
 // Can inline
 func TimeDateOnly(t time.Time) time.Time {
 	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
@@ -360,7 +383,18 @@ func RFC7232(t time.Time) string {
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601Compact(t time.Time) string {
-	return t.Format("20060102T150405-0700")
+	return iso8601CompactFormatter.Format(t)
+}
+
+// AppendISO8601Compact append an ISO8601Compact timestamp to b and return the
+// extended slice. Nothing is allocated if b has room.
+func AppendISO8601Compact(b []byte, t time.Time) []byte {
+	return iso8601CompactFormatter.Append(b, t)
+}
+
+// BufferISO8601Compact write an ISO8601Compact timestamp to an xfmt.Buffer
+func BufferISO8601Compact(buf *xfmt.Buffer, t time.Time) *xfmt.Buffer {
+	return iso8601CompactFormatter.Buffer(buf, t)
 }
 
 // ISO8601CompactMsec ISO-8601 timestamp with no seconds
@@ -369,7 +403,19 @@ func ISO8601Compact(t time.Time) string {
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601CompactMsec(t time.Time) string {
-	return t.Format("20060102T150405.000-0700")
+	return iso8601CompactMsecFormatter.Format(t)
+}
+
+// AppendISO8601CompactMsec append an ISO8601CompactMsec timestamp to b and
+// return the extended slice. Nothing is allocated if b has room.
+func AppendISO8601CompactMsec(b []byte, t time.Time) []byte {
+	return iso8601CompactMsecFormatter.Append(b, t)
+}
+
+// BufferISO8601CompactMsec write an ISO8601CompactMsec timestamp to an
+// xfmt.Buffer
+func BufferISO8601CompactMsec(buf *xfmt.Buffer, t time.Time) *xfmt.Buffer {
+	return iso8601CompactMsecFormatter.Buffer(buf, t)
 }
 
 // ISO8601 ISO-8601 timestamp long format string result
@@ -378,7 +424,18 @@ func ISO8601CompactMsec(t time.Time) string {
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601(t time.Time) string {
-	return t.Format("2006-01-02T15:04:05-07:00")
+	return iso8601Formatter.Format(t)
+}
+
+// AppendISO8601 append an ISO8601 timestamp to b and return the extended
+// slice. Nothing is allocated if b has room.
+func AppendISO8601(b []byte, t time.Time) []byte {
+	return iso8601Formatter.Append(b, t)
+}
+
+// BufferISO8601 write an ISO8601 timestamp to an xfmt.Buffer
+func BufferISO8601(buf *xfmt.Buffer, t time.Time) *xfmt.Buffer {
+	return iso8601Formatter.Buffer(buf, t)
 }
 
 // ISO8601Msec ISO-8601 longtimestamp with msec
@@ -387,10 +444,33 @@ func ISO8601(t time.Time) string {
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601Msec(t time.Time) string {
-	return t.Format("2006-01-02T15:04:05.000-07:00")
+	return iso8601MsecFormatter.Format(t)
+}
+
+// AppendISO8601Msec append an ISO8601Msec timestamp to b and return the
+// extended slice. Nothing is allocated if b has room.
+func AppendISO8601Msec(b []byte, t time.Time) []byte {
+	return iso8601MsecFormatter.Append(b, t)
 }
 
+// BufferISO8601Msec write an ISO8601Msec timestamp to an xfmt.Buffer
+func BufferISO8601Msec(buf *xfmt.Buffer, t time.Time) *xfmt.Buffer {
+	return iso8601MsecFormatter.Buffer(buf, t)
+}
+
+// Formatters for the ISO-8601 presets
+var (
+	iso8601Formatter            = mustISOFormatter(ISO8601Format)
+	iso8601MsecFormatter        = mustISOFormatter(ISO8601MsecFormat)
+	iso8601CompactFormatter     = mustISOFormatter(ISO8601CompactFormat)
+	iso8601CompactMsecFormatter = mustISOFormatter(ISO8601CompactMsecFormat)
+)
+
 // StartTimeIsBeforeEndTime if time 1 is before time 2 return true, else false
+//
+// Deprecated: only whole seconds are compared, so times less than a second
+// apart in the same second are not ordered. Use t1.Before(t2), or Interval and
+// IntervalSet for half-open spans compared to the nanosecond.
 func StartTimeIsBeforeEndTime(t1 time.Time, t2 time.Time) bool {
 	return t2.Unix()-t1.Unix() > 0
 }
@@ -0,0 +1,121 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/imarsman/timestamp/pkg/xfmt"
+	"github.com/matryer/is"
+)
+
+// appendFormats the Append functions and the Go layouts they must match
+var appendFormats = []struct {
+	name   string
+	layout string
+	format func(time.Time) string
+	append func([]byte, time.Time) []byte
+	buffer func(*xfmt.Buffer, time.Time) *xfmt.Buffer
+}{
+	{"ISO8601", "2006-01-02T15:04:05-07:00", timestamp.ISO8601, timestamp.AppendISO8601, timestamp.BufferISO8601},
+	{"ISO8601Msec", "2006-01-02T15:04:05.000-07:00", timestamp.ISO8601Msec, timestamp.AppendISO8601Msec, timestamp.BufferISO8601Msec},
+	{"ISO8601Compact", "20060102T150405-0700", timestamp.ISO8601Compact, timestamp.AppendISO8601Compact, timestamp.BufferISO8601Compact},
+	{"ISO8601CompactMsec", "20060102T150405.000-0700", timestamp.ISO8601CompactMsec, timestamp.AppendISO8601CompactMsec, timestamp.BufferISO8601CompactMsec},
+}
+
+func TestAppendISO8601(t *testing.T) {
+	is := is.New(t)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	is.NoErr(err) // Zone should load
+
+	times := []time.Time{
+		time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.UTC),
+		time.Date(2021, 7, 3, 7, 4, 5, 999999999, newYork),
+		time.Date(1999, 12, 31, 23, 59, 59, 0, time.FixedZone("", 5*60*60+30*60)),
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.FixedZone("", -(9*60*60+45*60))),
+		time.Date(9999, 12, 31, 23, 59, 59, 1000000, time.UTC),
+	}
+
+	for _, f := range appendFormats {
+		for _, ts := range times {
+			expected := ts.Format(f.layout)
+
+			prefix := []byte("ts=")
+			got := f.append(prefix, ts)
+			is.Equal(string(got), "ts="+expected) // Append should add to the slice
+
+			buf := new(xfmt.Buffer)
+			f.buffer(buf.S("ts="), ts).C(';')
+			is.Equal(string(buf.Bytes()), "ts="+expected+";") // Buffer should chain
+
+			is.Equal(f.format(ts), expected) // String form should match time.Format
+		}
+		t.Logf("%s %s", f.name, f.append(nil, times[1]))
+	}
+}
+
+func TestAppendISO8601Allocations(t *testing.T) {
+	is := is.New(t)
+
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.FixedZone("", -5*60*60))
+	b := make([]byte, 0, 64)
+	buf := make(xfmt.Buffer, 0, 64)
+
+	for _, f := range appendFormats {
+		allocs := testing.AllocsPerRun(100, func() {
+			b = f.append(b[:0], ts)
+		})
+		is.Equal(allocs, 0.0) // Append should not allocate
+		allocs = testing.AllocsPerRun(100, func() {
+			buf.Reset()
+			f.buffer(&buf, ts)
+		})
+		is.Equal(allocs, 0.0) // Buffer should not allocate
+	}
+}
+
+func BenchmarkAppendISO8601Msec(b *testing.B) {
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.FixedZone("", -5*60*60))
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = timestamp.AppendISO8601Msec(buf[:0], ts)
+	}
+}
+
+func BenchmarkAppendISO8601MsecNative(b *testing.B) {
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.FixedZone("", -5*60*60))
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = ts.AppendFormat(buf[:0], "2006-01-02T15:04:05.000-07:00")
+	}
+}
+
+func BenchmarkAppendISO8601Compact(b *testing.B) {
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.FixedZone("", -5*60*60))
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = timestamp.AppendISO8601Compact(buf[:0], ts)
+	}
+}
+
+func BenchmarkISO8601MsecFormat(b *testing.B) {
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.FixedZone("", -5*60*60))
+	var s string
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s = ts.Format("2006-01-02T15:04:05.000-07:00")
+	}
+	_ = s
+}
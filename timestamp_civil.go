@@ -0,0 +1,465 @@
+package timestamp
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// Date a calendar date with no time of day or location, such as a birthday
+// or a business date. Unlike a time.Time at midnight it does not move when
+// converted between zones.
+type Date struct {
+	Year  int
+	Month time.Month
+	Day   int
+}
+
+// TimeOfDay a wall clock time with no date or location
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+// DateTime a date and wall clock time with no location. It names the same
+// wall clock in every zone and becomes an instant only with In.
+type DateTime struct {
+	Date Date
+	Time TimeOfDay
+}
+
+// DSTPolicy how a wall clock time that a daylight saving change skips or
+// repeats is resolved to an instant
+type DSTPolicy int
+
+// Policies for resolving wall clock times to instants. A time in a gap, such
+// as 02:30 when clocks go from 02:00 to 03:00, is moved by the length of the
+// gap: earlier gives 01:30 and later gives 03:30. A time in an overlap, such
+// as 01:30 when clocks go back from 02:00 to 01:00, occurs twice and earlier
+// and later choose between the two.
+const (
+	DSTCompatible DSTPolicy = iota // later in a gap and earlier in an overlap
+	DSTEarlier                     // the earlier instant in a gap or overlap
+	DSTLater                       // the later instant in a gap or overlap
+	DSTReject                      // an error in a gap or overlap
+)
+
+// civilError make an error for a civil type
+func civilError(caller, reason, value string) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S(caller).S(": ").S(reason).S(" in input ").S(value)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// DateOf get the date of a time in its location
+func DateOf(t time.Time) Date {
+	year, month, day := t.Date()
+	return Date{Year: year, Month: month, Day: day}
+}
+
+// TimeOfDayOf get the wall clock of a time in its location
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	hour, minute, second := t.Clock()
+	return TimeOfDay{Hour: hour, Minute: minute, Second: second, Nanosecond: t.Nanosecond()}
+}
+
+// DateTimeOf get the date and wall clock of a time in its location
+func DateTimeOf(t time.Time) DateTime {
+	return DateTime{Date: DateOf(t), Time: TimeOfDayOf(t)}
+}
+
+// ParseDate parse an ISO-8601 date such as 2006-01-02 or 20060102. A time or
+// an offset in the input is an error, as is a date that does not exist.
+func ParseDate(value string) (d Date, err error) {
+	const caller = "timestamp.ParseDate"
+
+	parts, err := lexISOTimestamp(strings.TrimSpace(value), isoFull)
+	if err != nil {
+		return
+	}
+	if parts.precision != daySection || parts.zoneFound {
+		return Date{}, civilError(caller, "expected only a date", value)
+	}
+	d = Date{Year: parts.year, Month: time.Month(parts.month), Day: parts.day}
+	if !d.IsValid() {
+		return Date{}, civilError(caller, "date does not exist", value)
+	}
+
+	return
+}
+
+// ParseTimeOfDay parse an ISO-8601 wall clock time such as 15:04, 1504,
+// 15:04:05 or 15:04:05.123. The hour must be 0 to 23. An offset is an error
+// since a TimeOfDay has no location; use ParseISOClock for those.
+func ParseTimeOfDay(value string) (t TimeOfDay, err error) {
+	c, err := ParseISOClock(value)
+	if err != nil {
+		return
+	}
+	if c.HasOffset {
+		return TimeOfDay{}, civilError("timestamp.ParseTimeOfDay", "a TimeOfDay can't have an offset", value)
+	}
+
+	return c.TimeOfDay, nil
+}
+
+// ParseDateTime parse an ISO-8601 date and time with no offset, such as
+// 2006-01-02T15:04:05 or 2006-01-02 15:04:05.123. A date with no time is
+// midnight. An offset is an error since a DateTime has no location.
+func ParseDateTime(value string) (dt DateTime, err error) {
+	const caller = "timestamp.ParseDateTime"
+
+	parts, err := lexISOTimestamp(strings.TrimSpace(value), isoFull)
+	if err != nil {
+		return
+	}
+	if parts.zoneFound {
+		return DateTime{}, civilError(caller, "a DateTime can't have an offset", value)
+	}
+	dt = DateTime{
+		Date: Date{Year: parts.year, Month: time.Month(parts.month), Day: parts.day},
+		Time: TimeOfDay{Hour: parts.hour, Minute: parts.minute, Second: parts.second, Nanosecond: parts.nsec},
+	}
+	if !dt.IsValid() {
+		return DateTime{}, civilError(caller, "date or time does not exist", value)
+	}
+
+	return
+}
+
+// midnight get the date at midnight UTC, where every day is 24 hours
+func (d Date) midnight() time.Time {
+	return time.Date(d.Year, d.Month, d.Day, 0, 0, 0, 0, time.UTC)
+}
+
+// IsValid is the date a real day in the proleptic Gregorian calendar
+func (d Date) IsValid() bool {
+	return d.Month >= time.January && d.Month <= time.December && d.Day >= 1 && d.Day <= daysIn(d.Month, d.Year)
+}
+
+// IsZero is the date the zero Date
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// String get the date in ISO-8601 form, such as 2006-01-02
+func (d Date) String() string {
+	b := make([]byte, 0, 10)
+	return string(d.append(b))
+}
+
+// append append the date in ISO-8601 form
+func (d Date) append(b []byte) []byte {
+	b = appendInt(b, d.Year, 4, '0')
+	b = append(b, '-')
+	b = appendTwoDigits(b, int(d.Month), false)
+	b = append(b, '-')
+
+	return appendTwoDigits(b, d.Day, false)
+}
+
+// Weekday get the day of the week
+func (d Date) Weekday() time.Weekday {
+	return d.midnight().Weekday()
+}
+
+// AddDays add a number of days
+func (d Date) AddDays(n int) Date {
+	return DateOf(d.midnight().AddDate(0, 0, n))
+}
+
+// AddMonths add a number of months, clamping the day to the end of the month
+// so Jan 31 plus a month is the last day of February
+func (d Date) AddMonths(n int) Date {
+	return DateOf(addMonthsClamped(d.midnight(), n))
+}
+
+// DaysSince get the number of days from another date to this one
+func (d Date) DaysSince(other Date) int {
+	return int((d.midnight().Unix() - other.midnight().Unix()) / (24 * 60 * 60))
+}
+
+// Compare compare two dates, returning -1 if d is before other, 1 if it is
+// after and 0 if they are the same
+func (d Date) Compare(other Date) int {
+	switch {
+	case d.Year != other.Year:
+		return compareInts(d.Year, other.Year)
+	case d.Month != other.Month:
+		return compareInts(int(d.Month), int(other.Month))
+	}
+	return compareInts(d.Day, other.Day)
+}
+
+// Before is the date before another date
+func (d Date) Before(other Date) bool {
+	return d.Compare(other) < 0
+}
+
+// After is the date after another date
+func (d Date) After(other Date) bool {
+	return d.Compare(other) > 0
+}
+
+// At get the date at a time of day
+func (d Date) At(t TimeOfDay) DateTime {
+	return DateTime{Date: d, Time: t}
+}
+
+// In get the first instant of the date in a location. If a daylight saving
+// change skips midnight the policy decides the result, with DSTCompatible
+// giving the first instant after the gap.
+func (d Date) In(location *time.Location, policy DSTPolicy) (time.Time, error) {
+	return d.At(TimeOfDay{}).In(location, policy)
+}
+
+// MarshalText write the date in ISO-8601 form
+func (d Date) MarshalText() ([]byte, error) {
+	return d.append(make([]byte, 0, 10)), nil
+}
+
+// UnmarshalText read a date in ISO-8601 form
+func (d *Date) UnmarshalText(data []byte) (err error) {
+	*d, err = ParseDate(string(data))
+	return
+}
+
+// timeOfDayFromDuration get the wall clock a duration after midnight
+func timeOfDayFromDuration(d time.Duration) TimeOfDay {
+	return TimeOfDay{
+		Hour:       int(d / time.Hour),
+		Minute:     int(d / time.Minute % 60),
+		Second:     int(d / time.Second % 60),
+		Nanosecond: int(d % time.Second),
+	}
+}
+
+// sinceMidnight get the time from midnight on a day without a daylight
+// saving change
+func (t TimeOfDay) sinceMidnight() time.Duration {
+	return time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+		time.Duration(t.Second)*time.Second + time.Duration(t.Nanosecond)
+}
+
+// IsValid are the parts in range, with no leap seconds
+func (t TimeOfDay) IsValid() bool {
+	return t.Hour >= 0 && t.Hour < 24 && t.Minute >= 0 && t.Minute < 60 &&
+		t.Second >= 0 && t.Second < 60 && t.Nanosecond >= 0 && t.Nanosecond < int(time.Second)
+}
+
+// IsZero is the time midnight
+func (t TimeOfDay) IsZero() bool {
+	return t == TimeOfDay{}
+}
+
+// String get the time in ISO-8601 form, such as 15:04:05 or 15:04:05.123,
+// with trailing zeros removed from the fraction
+func (t TimeOfDay) String() string {
+	b := make([]byte, 0, 18)
+	return string(t.append(b))
+}
+
+// append append the time in ISO-8601 form
+func (t TimeOfDay) append(b []byte) []byte {
+	b = appendTwoDigits(b, t.Hour, false)
+	b = append(b, ':')
+	b = appendTwoDigits(b, t.Minute, false)
+	b = append(b, ':')
+	b = appendTwoDigits(b, t.Second, false)
+
+	return appendFraction(b, t.Nanosecond, 9, true, ".")
+}
+
+// Add add a duration, wrapping around midnight
+func (t TimeOfDay) Add(d time.Duration) TimeOfDay {
+	since := (t.sinceMidnight() + d%(24*time.Hour)) % (24 * time.Hour)
+	if since < 0 {
+		since += 24 * time.Hour
+	}
+	return timeOfDayFromDuration(since)
+}
+
+// Compare compare two times of day, returning -1 if t is before other, 1 if
+// it is after and 0 if they are the same
+func (t TimeOfDay) Compare(other TimeOfDay) int {
+	a, b := t.sinceMidnight(), other.sinceMidnight()
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Before is the time of day before another
+func (t TimeOfDay) Before(other TimeOfDay) bool {
+	return t.Compare(other) < 0
+}
+
+// After is the time of day after another
+func (t TimeOfDay) After(other TimeOfDay) bool {
+	return t.Compare(other) > 0
+}
+
+// MarshalText write the time in ISO-8601 form
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return t.append(make([]byte, 0, 18)), nil
+}
+
+// UnmarshalText read a time such as 15:04:05
+func (t *TimeOfDay) UnmarshalText(data []byte) (err error) {
+	*t, err = ParseTimeOfDay(string(data))
+	return
+}
+
+// wall get the date and time as a UTC time, where every day is 24 hours
+func (dt DateTime) wall() time.Time {
+	return time.Date(dt.Date.Year, dt.Date.Month, dt.Date.Day,
+		dt.Time.Hour, dt.Time.Minute, dt.Time.Second, dt.Time.Nanosecond, time.UTC)
+}
+
+// IsValid are the date and time valid
+func (dt DateTime) IsValid() bool {
+	return dt.Date.IsValid() && dt.Time.IsValid()
+}
+
+// IsZero is the date and time the zero DateTime
+func (dt DateTime) IsZero() bool {
+	return dt == DateTime{}
+}
+
+// String get the date and time in ISO-8601 form, such as
+// 2006-01-02T15:04:05.123
+func (dt DateTime) String() string {
+	b := make([]byte, 0, 29)
+	return string(dt.append(b))
+}
+
+// append append the date and time in ISO-8601 form
+func (dt DateTime) append(b []byte) []byte {
+	b = dt.Date.append(b)
+	b = append(b, 'T')
+
+	return dt.Time.append(b)
+}
+
+// AddDays add a number of days keeping the time of day
+func (dt DateTime) AddDays(n int) DateTime {
+	return DateTime{Date: dt.Date.AddDays(n), Time: dt.Time}
+}
+
+// AddMonths add a number of months keeping the time of day, clamping the day
+// to the end of the month
+func (dt DateTime) AddMonths(n int) DateTime {
+	return DateTime{Date: dt.Date.AddMonths(n), Time: dt.Time}
+}
+
+// Add add a duration to the wall clock, where every day is 24 hours
+func (dt DateTime) Add(d time.Duration) DateTime {
+	return DateTimeOf(dt.wall().Add(d))
+}
+
+// Sub get the wall clock duration between two date times, where every day is
+// 24 hours
+func (dt DateTime) Sub(other DateTime) time.Duration {
+	return dt.wall().Sub(other.wall())
+}
+
+// Compare compare two date times, returning -1 if dt is before other, 1 if it
+// is after and 0 if they are the same
+func (dt DateTime) Compare(other DateTime) int {
+	if c := dt.Date.Compare(other.Date); c != 0 {
+		return c
+	}
+	return dt.Time.Compare(other.Time)
+}
+
+// Before is the date time before another
+func (dt DateTime) Before(other DateTime) bool {
+	return dt.Compare(other) < 0
+}
+
+// After is the date time after another
+func (dt DateTime) After(other DateTime) bool {
+	return dt.Compare(other) > 0
+}
+
+// In get the instant the date and time name in a location. Wall clock times
+// skipped or repeated by a daylight saving change are resolved with the
+// policy, and DSTReject gives an error for them.
+func (dt DateTime) In(location *time.Location, policy DSTPolicy) (time.Time, error) {
+	if location == nil {
+		location = time.UTC
+	}
+	wall := dt.wall()
+
+	// Try the offsets in effect a day either side, which covers any single
+	// change. A candidate is good if it shows the same wall clock.
+	_, before := wall.Add(-24 * time.Hour).In(location).Zone()
+	_, after := wall.Add(24 * time.Hour).In(location).Zone()
+	first := wall.Add(-time.Duration(before) * time.Second).In(location)
+	second := wall.Add(-time.Duration(after) * time.Second).In(location)
+	if first.After(second) {
+		first, second = second, first
+	}
+	firstGood := sameWallClock(first, wall)
+	secondGood := sameWallClock(second, wall)
+
+	switch {
+	case firstGood && secondGood && first.Equal(second):
+		return first, nil
+	case firstGood != secondGood:
+		if firstGood {
+			return first, nil
+		}
+		return second, nil
+	case before == after:
+		// Neither matched with no change in offset, such as with several
+		// changes in a day, so leave it to the time package
+		return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(),
+			wall.Second(), wall.Nanosecond(), location), nil
+	}
+
+	overlap := firstGood // both good means the wall clock happens twice
+	switch {
+	case policy == DSTReject:
+		reason := "wall clock time is skipped by a daylight saving change in " + location.String()
+		if overlap {
+			reason = "wall clock time is repeated by a daylight saving change in " + location.String()
+		}
+		return time.Time{}, civilError("timestamp.DateTime.In", reason, dt.String())
+	case policy == DSTEarlier, policy == DSTCompatible && overlap:
+		return first, nil
+	}
+
+	return second, nil
+}
+
+// MarshalText write the date and time in ISO-8601 form
+func (dt DateTime) MarshalText() ([]byte, error) {
+	return dt.append(make([]byte, 0, 29)), nil
+}
+
+// UnmarshalText read a date and time in ISO-8601 form
+func (dt *DateTime) UnmarshalText(data []byte) (err error) {
+	*dt, err = ParseDateTime(string(data))
+	return
+}
+
+// compareInts compare two ints, returning -1, 0 or 1
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,156 @@
+package timestamp_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestParseCivil(t *testing.T) {
+	is := is.New(t)
+
+	d, err := timestamp.ParseDate("2024-02-29")
+	is.NoErr(err)
+	is.Equal(d, timestamp.Date{Year: 2024, Month: time.February, Day: 29})
+	is.Equal(d.String(), "2024-02-29")
+
+	d, err = timestamp.ParseDate("20240301")
+	is.NoErr(err)
+	is.Equal(d.String(), "2024-03-01")
+
+	tod, err := timestamp.ParseTimeOfDay("15:04:05.120")
+	is.NoErr(err)
+	is.Equal(tod, timestamp.TimeOfDay{Hour: 15, Minute: 4, Second: 5, Nanosecond: 120000000})
+	is.Equal(tod.String(), "15:04:05.12")
+
+	dt, err := timestamp.ParseDateTime("2024-03-10 02:30:00")
+	is.NoErr(err)
+	is.Equal(dt.String(), "2024-03-10T02:30:00")
+
+	for _, input := range []string{"2023-02-29", "2024-02-29T10:00:00", "2024-02-29Z", "2024-13-01"} {
+		_, err := timestamp.ParseDate(input)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+	for _, input := range []string{"24:00", "12:60", "12:5", "12:00Z"} {
+		_, err := timestamp.ParseTimeOfDay(input)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+	for _, input := range []string{"2024-03-10T02:30:00Z", "2024-03-10T02:30:00+01:00", "2024-02-30T10:00:00"} {
+		_, err := timestamp.ParseDateTime(input)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+}
+
+func TestCivilArithmetic(t *testing.T) {
+	is := is.New(t)
+
+	d := timestamp.Date{Year: 2024, Month: time.January, Day: 31}
+	is.Equal(d.AddMonths(1).String(), "2024-02-29") // Month should clamp
+	is.Equal(d.AddMonths(13).String(), "2025-02-28")
+	is.Equal(d.AddDays(30).String(), "2024-03-01")
+	is.Equal(d.AddDays(-31).String(), "2023-12-31")
+	is.Equal(d.AddDays(366).DaysSince(d), 366)
+	is.Equal(d.Weekday(), time.Wednesday)
+
+	is.True(d.Before(d.AddDays(1)))
+	is.True(d.AddMonths(1).After(d))
+	is.Equal(d.Compare(d), 0)
+
+	tod := timestamp.TimeOfDay{Hour: 23, Minute: 30}
+	is.Equal(tod.Add(time.Hour).String(), "00:30:00")
+	is.Equal(tod.Add(-24*time.Hour-time.Minute).String(), "23:29:00")
+
+	dt := d.At(tod)
+	is.Equal(dt.Add(time.Hour).String(), "2024-02-01T00:30:00")
+	is.Equal(dt.AddMonths(1).String(), "2024-02-29T23:30:00")
+	is.Equal(dt.Add(time.Hour).Sub(dt), time.Hour)
+	is.True(dt.Before(dt.AddDays(1)))
+}
+
+func TestCivilIn(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	// 02:30 is skipped when clocks go forward on 2024-03-10
+	gap := timestamp.DateTime{
+		Date: timestamp.Date{Year: 2024, Month: time.March, Day: 10},
+		Time: timestamp.TimeOfDay{Hour: 2, Minute: 30},
+	}
+	// 01:30 happens twice when clocks go back on 2024-11-03
+	overlap := timestamp.DateTime{
+		Date: timestamp.Date{Year: 2024, Month: time.November, Day: 3},
+		Time: timestamp.TimeOfDay{Hour: 1, Minute: 30},
+	}
+
+	tests := []struct {
+		dt       timestamp.DateTime
+		policy   timestamp.DSTPolicy
+		expected string
+	}{
+		{gap, timestamp.DSTCompatible, "2024-03-10T03:30:00-04:00"},
+		{gap, timestamp.DSTEarlier, "2024-03-10T01:30:00-05:00"},
+		{gap, timestamp.DSTLater, "2024-03-10T03:30:00-04:00"},
+		{overlap, timestamp.DSTCompatible, "2024-11-03T01:30:00-04:00"},
+		{overlap, timestamp.DSTEarlier, "2024-11-03T01:30:00-04:00"},
+		{overlap, timestamp.DSTLater, "2024-11-03T01:30:00-05:00"},
+		{gap.AddDays(1), timestamp.DSTReject, "2024-03-11T02:30:00-04:00"},
+	}
+
+	for _, test := range tests {
+		got, err := test.dt.In(toronto, test.policy)
+		is.NoErr(err)                                     // Should resolve
+		is.Equal(got.Format(time.RFC3339), test.expected) // Instant should match
+	}
+
+	_, err = gap.In(toronto, timestamp.DSTReject)
+	is.True(err != nil) // Skipped time should be rejected
+	t.Log(err)
+	_, err = overlap.In(toronto, timestamp.DSTReject)
+	is.True(err != nil) // Repeated time should be rejected
+	t.Log(err)
+
+	// A date stays the same date whichever zone a time is read in
+	instant := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+	is.Equal(timestamp.DateOf(instant).String(), "2024-06-01")
+	is.Equal(timestamp.DateOf(instant.In(toronto)).String(), "2024-05-31")
+
+	// Midnight in Santiago is skipped on 2022-09-11
+	santiago, err := time.LoadLocation("America/Santiago")
+	is.NoErr(err)
+	start, err := timestamp.Date{Year: 2022, Month: time.September, Day: 11}.In(santiago, timestamp.DSTCompatible)
+	is.NoErr(err)
+	is.Equal(start.Format(time.RFC3339), "2022-09-11T01:00:00-03:00")
+}
+
+func TestCivilJSON(t *testing.T) {
+	is := is.New(t)
+
+	type record struct {
+		Birthday timestamp.Date      `json:"birthday"`
+		Alarm    timestamp.TimeOfDay `json:"alarm"`
+		Meeting  timestamp.DateTime  `json:"meeting"`
+	}
+
+	in := record{
+		Birthday: timestamp.Date{Year: 1990, Month: time.July, Day: 4},
+		Alarm:    timestamp.TimeOfDay{Hour: 6, Minute: 45},
+		Meeting:  timestamp.DateTime{Date: timestamp.Date{Year: 2024, Month: time.May, Day: 1}, Time: timestamp.TimeOfDay{Hour: 9, Nanosecond: 500000000}},
+	}
+	data, err := json.Marshal(in)
+	is.NoErr(err)
+	is.Equal(string(data), `{"birthday":"1990-07-04","alarm":"06:45:00","meeting":"2024-05-01T09:00:00.5"}`)
+
+	var out record
+	is.NoErr(json.Unmarshal(data, &out))
+	is.Equal(out, in) // Should round trip
+
+	is.True(json.Unmarshal([]byte(`{"birthday":"1990-02-30"}`), &out) != nil) // Bad date should fail
+}
@@ -0,0 +1,119 @@
+package timestamp
+
+import (
+	"strings"
+	"time"
+)
+
+// ISOClock a time of day read from an ISO-8601 time with no date, along with
+// its offset if it had one
+type ISOClock struct {
+	TimeOfDay
+	HasOffset bool // was there an offset such as Z or +05:30
+	Offset    int  // offset from UTC in seconds
+}
+
+// Rollover how a time of day is placed on a day near a reference time
+type Rollover int
+
+// Rollover rules. With RolloverNearest a log line at 23:59 read just after
+// midnight lands on the day before and a line at 00:01 read just before
+// midnight lands on the day after.
+const (
+	RolloverNone     Rollover = iota // on the date of the reference time
+	RolloverNearest                  // the day before, of or after, whichever is nearest the reference time
+	RolloverForward                  // the first occurrence at or after the reference time
+	RolloverBackward                 // the last occurrence at or before the reference time
+)
+
+// ParseISOClock parse an ISO-8601 time of day with no date, such as 15:04,
+// T15:04, 1504, 15:04:05.123Z or 150405+0530. Minutes and seconds may be left
+// out and default to zero. The hour must be 0 to 23.
+func ParseISOClock(value string) (c ISOClock, err error) {
+	parts, err := lexISOTimestamp(strings.TrimSpace(value), isoTimeOnly)
+	if err != nil {
+		return
+	}
+	c = ISOClock{
+		TimeOfDay: TimeOfDay{Hour: parts.hour, Minute: parts.minute, Second: parts.second, Nanosecond: parts.nsec},
+		HasOffset: parts.zoneFound,
+		Offset:    parts.offsetSec,
+	}
+	if !c.TimeOfDay.IsValid() {
+		return ISOClock{}, civilError("timestamp.ParseISOClock", "time of day out of range", value)
+	}
+
+	return
+}
+
+// location get the location the clock is read in, which is a fixed zone for
+// its offset if it has one
+func (c ISOClock) location(location *time.Location) *time.Location {
+	switch {
+	case !c.HasOffset && location != nil:
+		return location
+	case !c.HasOffset, c.Offset == 0:
+		return time.UTC
+	}
+	return LocationFromOffset(c.Offset)
+}
+
+// On get the instant of the clock on a date. A clock with no offset is read
+// in location, with a wall clock skipped by a daylight saving change moved
+// forward by the length of the gap.
+func (c ISOClock) On(d Date, location *time.Location) time.Time {
+	// DSTCompatible never gives an error
+	t, _ := d.At(c.TimeOfDay).In(c.location(location), DSTCompatible)
+	return t
+}
+
+// Near get the instant of the clock on a day near a reference time. A clock
+// with no offset is read in the location of the reference time, and a clock
+// with an offset on the date of the reference time at that offset.
+func (c ISOClock) Near(ref time.Time, rollover Rollover) time.Time {
+	location := c.location(ref.Location())
+	day := DateOf(ref.In(location))
+	on := func(days int) time.Time {
+		return c.On(day.AddDays(days), location)
+	}
+
+	t := on(0)
+	switch rollover {
+	case RolloverNearest:
+		for _, days := range [...]int{-1, 1} {
+			if other := on(days); absDuration(other.Sub(ref)) < absDuration(t.Sub(ref)) {
+				t = other
+			}
+		}
+	case RolloverForward:
+		if t.Before(ref) {
+			t = on(1)
+		}
+	case RolloverBackward:
+		if t.After(ref) {
+			t = on(-1)
+		}
+	}
+
+	return t
+}
+
+// ParseISOTimeNear parse an ISO-8601 time of day with no date and place it on
+// a day near a reference time using a rollover rule. This suits log formats
+// that only record the time of day, where the time the log was read or the
+// previous line gives the date.
+func ParseISOTimeNear(value string, ref time.Time, rollover Rollover) (t time.Time, err error) {
+	c, err := ParseISOClock(value)
+	if err != nil {
+		return
+	}
+	return c.Near(ref, rollover), nil
+}
+
+// absDuration get the absolute value of a duration
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,94 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestParseISOClock(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input     string
+		expected  string
+		hasOffset bool
+		offset    int
+	}{
+		{"T15:04", "15:04:00", false, 0},
+		{"15:04:05.123Z", "15:04:05.123", true, 0},
+		{"1504", "15:04:00", false, 0},
+		{"150405", "15:04:05", false, 0},
+		{"T15", "15:00:00", false, 0},
+		{"15:04:05+05:30", "15:04:05", true, 5*3600 + 30*60},
+		{"1504-0700", "15:04:00", true, -7 * 3600},
+		{"00:00:00.000000001", "00:00:00.000000001", false, 0},
+	}
+
+	for _, test := range tests {
+		c, err := timestamp.ParseISOClock(test.input)
+		is.NoErr(err)                                 // Should parse without error
+		is.Equal(c.TimeOfDay.String(), test.expected) // Clock should match
+		is.Equal(c.HasOffset, test.hasOffset)         // Offset presence should match
+		is.Equal(c.Offset, test.offset)               // Offset should match
+	}
+
+	for _, input := range []string{"", "T", "24:00", "15:60", "15:04:05+0", "1", "15:04:05X"} {
+		_, err := timestamp.ParseISOClock(input)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+
+	// The full parser still reads leading digits as a year
+	_, err := timestamp.ParseISOTimestamp("15:04:05", time.UTC)
+	is.True(err != nil)
+}
+
+func TestParseISOTimeNear(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	// Just after midnight local time
+	ref := time.Date(2024, 5, 2, 0, 5, 0, 0, toronto)
+
+	tests := []struct {
+		input    string
+		rollover timestamp.Rollover
+		expected string
+	}{
+		{"23:59:30", timestamp.RolloverNone, "2024-05-02T23:59:30-04:00"},
+		{"23:59:30", timestamp.RolloverNearest, "2024-05-01T23:59:30-04:00"},
+		{"23:59:30", timestamp.RolloverBackward, "2024-05-01T23:59:30-04:00"},
+		{"00:10", timestamp.RolloverNearest, "2024-05-02T00:10:00-04:00"},
+		{"00:01", timestamp.RolloverForward, "2024-05-03T00:01:00-04:00"},
+		{"00:05", timestamp.RolloverForward, "2024-05-02T00:05:00-04:00"},
+		{"12:00", timestamp.RolloverNearest, "2024-05-02T12:00:00-04:00"},
+		// With an offset the date is the date of the reference at that offset
+		{"03:55Z", timestamp.RolloverNearest, "2024-05-02T03:55:00Z"},
+		{"04:10Z", timestamp.RolloverBackward, "2024-05-01T04:10:00Z"},
+		{"04:10Z", timestamp.RolloverForward, "2024-05-02T04:10:00Z"},
+		{"04:00Z", timestamp.RolloverForward, "2024-05-03T04:00:00Z"},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.ParseISOTimeNear(test.input, ref, test.rollover)
+		is.NoErr(err)                                     // Should parse without error
+		is.Equal(got.Format(time.RFC3339), test.expected) // Instant should match
+	}
+
+	// A skipped wall clock moves forward
+	ref = time.Date(2024, 3, 10, 1, 0, 0, 0, toronto)
+	got, err := timestamp.ParseISOTimeNear("02:30", ref, timestamp.RolloverNone)
+	is.NoErr(err)
+	is.Equal(got.Format(time.RFC3339), "2024-03-10T03:30:00-04:00")
+
+	// A clock can be placed on a civil date
+	c, err := timestamp.ParseISOClock("T0930")
+	is.NoErr(err)
+	on := c.On(timestamp.Date{Year: 2024, Month: time.July, Day: 1}, toronto)
+	is.Equal(on.Format(time.RFC3339), "2024-07-01T09:30:00-04:00")
+}
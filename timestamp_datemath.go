@@ -0,0 +1,146 @@
+package timestamp
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// DateMath evaluates Elasticsearch and Grafana style date math expressions.
+//
+// An expression starts with an anchor, either "now" or a timestamp followed by
+// "||", and is followed by any number of operations:
+//
+//	+1d   add one day
+//	-2M   subtract two months
+//	/d    round to the day
+//
+// Units are y (year), Q (quarter), M (month), w (week), d (day), h or H
+// (hour), m (minute), s (second) and ms (millisecond). A missing number is
+// taken as 1.
+//
+// Anchor timestamps are parsed with ParseInLocation so any supported format
+// can be used. Operations are evaluated in Location. Day and larger steps are
+// applied to the calendar date so a day is not assumed to be 24 hours across
+// a daylight saving change, and month and year steps are clamped to the end
+// of the month so that 2024-01-31||+1M is 2024-02-29.
+//
+// Rounding is down to the first instant of the unit unless RoundUp is set, in
+// which case it is up to the last instant of the unit. Round up is used for
+// inclusive range ends such as "lte". Weeks start on Monday.
+type DateMath struct {
+	Location *time.Location   // location for rounding and anchors without a zone, UTC if nil
+	Now      func() time.Time // source for "now", time.Now if nil
+	RoundUp  bool             // round to the end of units rather than the start
+}
+
+// ParseDateMath evaluate a date math expression with a fixed value for now.
+// See DateMath for the expression syntax.
+func ParseDateMath(expr string, now time.Time, location *time.Location, roundUp bool) (time.Time, error) {
+	dm := DateMath{
+		Location: location,
+		Now:      func() time.Time { return now },
+		RoundUp:  roundUp,
+	}
+	return dm.Parse(expr)
+}
+
+// dateMathError make an error pointing at a position in a date math expression
+func dateMathError(reason string, expr string, pos int) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.DateMath: ").S(reason)
+	if pos < len(expr) {
+		xfmtBuf.S(" '").S(expr[pos:]).S("'").C('@').D(pos)
+	}
+	xfmtBuf.S(" in input ").S(expr)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// Parse evaluate a date math expression
+func (dm DateMath) Parse(expr string) (t time.Time, err error) {
+	location := dm.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	expr = strings.TrimSpace(expr)
+	var pos int
+	switch {
+	case strings.HasPrefix(expr, "now"):
+		if dm.Now != nil {
+			t = dm.Now()
+		} else {
+			t = time.Now()
+		}
+		pos = len("now")
+	default:
+		i := strings.Index(expr, "||")
+		if i < 0 {
+			// A bare timestamp with no math
+			i = len(expr)
+		}
+		t, err = ParseInLocation(expr[:i], location)
+		if err != nil {
+			return time.Time{}, dateMathError("invalid anchor", expr, 0)
+		}
+		pos = i + 2
+	}
+	t = t.In(location)
+
+	for pos < len(expr) {
+		op := expr[pos]
+		if op != '+' && op != '-' && op != '/' {
+			return time.Time{}, dateMathError("expected +, - or /", expr, pos)
+		}
+		start := pos
+		pos++
+
+		n := 1
+		if op != '/' {
+			digitStart := pos
+			for pos < len(expr) && expr[pos] >= '0' && expr[pos] <= '9' {
+				pos++
+			}
+			if pos > digitStart {
+				n = 0
+				for _, c := range expr[digitStart:pos] {
+					// Keep well inside int range on 32 bit platforms
+					if n = n*10 + int(c-'0'); n > 1<<30 {
+						return time.Time{}, dateMathError("number out of range", expr, start)
+					}
+				}
+			}
+		}
+
+		unitStart := pos
+		for pos < len(expr) && expr[pos] != '+' && expr[pos] != '-' && expr[pos] != '/' {
+			pos++
+		}
+		unit, ok := ParseUnit(expr[unitStart:pos])
+		if !ok || unit < Millisecond {
+			return time.Time{}, dateMathError("unknown unit", expr, unitStart)
+		}
+		if _, ok := unitsDuration(n, unit); !ok && !unit.IsCalendar() {
+			return time.Time{}, dateMathError("step too large", expr, start)
+		}
+
+		switch op {
+		case '+':
+			t = addUnits(t, n, unit)
+		case '-':
+			t = addUnits(t, -n, unit)
+		default:
+			if dm.RoundUp {
+				t = endOfUnit(t, unit)
+			} else {
+				t = startOfUnit(t, unit)
+			}
+		}
+	}
+
+	return
+}
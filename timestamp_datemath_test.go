@@ -0,0 +1,68 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestDateMath(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err) // Location should load
+
+	now := time.Date(2024, 3, 13, 14, 25, 36, 123456789, toronto)
+	dm := timestamp.DateMath{
+		Location: toronto,
+		Now:      func() time.Time { return now },
+	}
+	dmUp := dm
+	dmUp.RoundUp = true
+
+	tests := []struct {
+		dm       timestamp.DateMath
+		expr     string
+		expected time.Time
+	}{
+		{dm, "now", now},
+		{dm, "now-1d/d", time.Date(2024, 3, 12, 0, 0, 0, 0, toronto)},
+		{dmUp, "now-1d/d", time.Date(2024, 3, 12, 23, 59, 59, 999999999, toronto)},
+		{dm, "now/M+1h", time.Date(2024, 3, 1, 1, 0, 0, 0, toronto)},
+		{dm, "now/w", time.Date(2024, 3, 11, 0, 0, 0, 0, toronto)},
+		{dm, "now/y", time.Date(2024, 1, 1, 0, 0, 0, 0, toronto)},
+		{dmUp, "now/Q", time.Date(2024, 3, 31, 23, 59, 59, 999999999, toronto)},
+		{dm, "now/s", time.Date(2024, 3, 13, 14, 25, 36, 0, toronto)},
+		{dm, "now-h", time.Date(2024, 3, 13, 13, 25, 36, 123456789, toronto)},
+		// Day steps keep the wall clock across the 2024-03-10 change
+		{dm, "now-3d", time.Date(2024, 3, 10, 14, 25, 36, 123456789, toronto)},
+		// Month steps are clamped to the end of the month
+		{dm, "2024-01-31T10:00:00Z||+1M", time.Date(2024, 2, 29, 5, 0, 0, 0, toronto)},
+		{dm, "2024-01-31||+1M/d", time.Date(2024, 2, 29, 0, 0, 0, 0, toronto)},
+		{dm, "2024-02-29||+1y", time.Date(2025, 2, 28, 0, 0, 0, 0, toronto)},
+		{dm, "2024-02-29||-12M", time.Date(2023, 2, 28, 0, 0, 0, 0, toronto)},
+	}
+
+	for _, test := range tests {
+		got, err := test.dm.Parse(test.expr)
+		is.NoErr(err) // Expression should parse
+		t.Logf("expr %q got %v", test.expr, got)
+		is.True(got.Equal(test.expected)) // Result should match expected
+	}
+
+	got, err := timestamp.ParseDateMath("now/d", now, time.UTC, false)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2024, 3, 13, 0, 0, 0, 0, time.UTC))) // Rounding is in the supplied location
+
+	for _, expr := range []string{
+		"now+1x", "now*2d", "yesterday||+1d", "now+99999999999d",
+		// Steps too large for a duration
+		"now+1000000000h", "now-1000000000h", "now+3000000h", "now-3000000h", "now+200000000000s",
+	} {
+		_, err = dm.Parse(expr)
+		t.Logf("expr %q error %v", expr, err)
+		is.True(err != nil) // Should be an error
+	}
+}
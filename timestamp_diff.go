@@ -0,0 +1,159 @@
+package timestamp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/JohnCGriffin/overflow"
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// Diff get the calendar period from start to end in a location, such as
+// 1 year 2 months 3 days 04:05:06, with the months and days following the
+// calendar of the location rather than fixed lengths. Whole months are taken
+// first, then whole days, then the remaining clock, so adding the result to
+// start with Period.AddTo in the same location gives end. All parts share a
+// sign. When end is before start the result is the forward difference from
+// end to start negated. A nil location uses the location of start.
+//
+// Unlike a time.Duration the period does not overflow for times more than
+// about 292 years apart. FormatPostgresInterval formats the result.
+func Diff(start, end time.Time, location *time.Location) Period {
+	if location == nil {
+		location = start.Location()
+	}
+	if end.Before(start) {
+		return Diff(end, start, location).Neg()
+	}
+	start, end = start.In(location), end.In(location)
+
+	months := wholeMonths(start, end)
+	t := addMonthsClamped(start, months)
+	days := wholeDays(t, end)
+	t = t.AddDate(0, 0, days)
+
+	return Period{Months: months, Days: days, Clock: end.Sub(t)}
+}
+
+// DiffIn get the number of whole units from start to end in a location,
+// truncated toward zero. Calendar units follow the calendar of the location
+// as Diff does, so Jan 31 to Feb 29 is one month and a day across a daylight
+// saving change is one day. Exact units are counted from the seconds and
+// nanoseconds of the times so they do not overflow as time.Duration does past
+// about 292 years. An error is returned for an unknown unit or a count that
+// does not fit in an int64, which can only happen for units below a second.
+// A nil location uses the location of start.
+func DiffIn(start, end time.Time, unit Unit, location *time.Location) (int64, error) {
+	if location == nil {
+		location = start.Location()
+	}
+	if end.Before(start) {
+		n, err := DiffIn(end, start, unit, location)
+		return -n, err
+	}
+	start, end = start.In(location), end.In(location)
+
+	switch unit {
+	case Day:
+		return int64(wholeDays(start, end)), nil
+	case Week:
+		return int64(wholeDays(start, end) / 7), nil
+	case Month:
+		return int64(wholeMonths(start, end)), nil
+	case Quarter:
+		return int64(wholeMonths(start, end) / 3), nil
+	case Year:
+		return int64(wholeMonths(start, end) / 12), nil
+	}
+
+	// Borrow a second so the seconds and nanoseconds are both positive
+	seconds, nanos := end.Unix()-start.Unix(), int64(end.Nanosecond()-start.Nanosecond())
+	if nanos < 0 {
+		seconds, nanos = seconds-1, nanos+int64(time.Second)
+	}
+
+	per := int64(unit.Duration())
+	if per == 0 {
+		return 0, diffError(unit, "unknown unit", start, end)
+	}
+	if per >= int64(time.Second) {
+		return seconds / (per / int64(time.Second)), nil
+	}
+
+	scaled, okMul := overflow.Mul64(seconds, int64(time.Second)/per)
+	n, okAdd := overflow.Add64(scaled, nanos/per)
+	if !okMul || !okAdd {
+		return 0, diffError(unit, "count overflows int64", start, end)
+	}
+
+	return n, nil
+}
+
+// diffError make an error for a count of units between two times
+func diffError(unit Unit, reason string, start, end time.Time) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.DiffIn: ").S(unit.String()).S(" ").S(reason).S(" from ").
+		S(start.Format(time.RFC3339Nano)).S(" to ").S(end.Format(time.RFC3339Nano))
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// wholeMonths get the number of whole months from start to end, with start
+// not after end, using months clamped to the end of the month
+func wholeMonths(start, end time.Time) int {
+	months := (end.Year()-start.Year())*12 + int(end.Month()) - int(start.Month())
+	// Adding months never moves backward, so at most one step back is needed
+	if months > 0 && addMonthsClamped(start, months).After(end) {
+		months--
+	}
+	return months
+}
+
+// wholeDays get the number of whole calendar days from start to end, with
+// start not after end, keeping the wall clock of start
+func wholeDays(start, end time.Time) int {
+	days := DateOf(end).DaysSince(DateOf(start))
+	if days > 0 && start.AddDate(0, 0, days).After(end) {
+		days--
+	}
+	return days
+}
+
+// LeapDayPolicy when someone born on February 29 has their birthday in a
+// year that is not a leap year
+type LeapDayPolicy int
+
+// Leap day birthday policies
+const (
+	LeapDayFebruary28 LeapDayPolicy = iota // the last day of February
+	LeapDayMarch1                          // the day after February 28
+)
+
+// Age get the age in whole years on a date of someone born on birth, which
+// goes up on each birthday. A birthday on February 29 falls on February 28 or
+// March 1 in other years depending on policy. Use DateOf on a time in the
+// right location to get the dates. The age is negative if on is before birth.
+func Age(birth, on Date, policy LeapDayPolicy) int {
+	if on.Before(birth) {
+		return -Age(on, birth, policy)
+	}
+
+	age := on.Year - birth.Year
+	if on.Before(birthday(birth, on.Year, policy)) {
+		age--
+	}
+	return age
+}
+
+// birthday get the date of the anniversary of birth in a year
+func birthday(birth Date, year int, policy LeapDayPolicy) Date {
+	d := Date{Year: year, Month: birth.Month, Day: birth.Day}
+	if birth.Month == time.February && birth.Day == 29 && !isLeap(year) {
+		if policy == LeapDayMarch1 {
+			return Date{Year: year, Month: time.March, Day: 1}
+		}
+		d.Day = 28
+	}
+	return d
+}
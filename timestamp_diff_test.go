@@ -0,0 +1,120 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestDiff(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	tests := []struct {
+		start    time.Time
+		end      time.Time
+		expected string
+	}{
+		{time.Date(2023, 1, 1, 0, 0, 0, 0, toronto), time.Date(2024, 3, 4, 4, 5, 6, 0, toronto), "1 year 2 mons 3 days 04:05:06"},
+		{time.Date(2024, 1, 31, 0, 0, 0, 0, toronto), time.Date(2024, 2, 29, 0, 0, 0, 0, toronto), "1 mon"},
+		{time.Date(2023, 1, 31, 0, 0, 0, 0, toronto), time.Date(2023, 3, 1, 0, 0, 0, 0, toronto), "1 mon 1 day"},
+		{time.Date(2024, 1, 31, 10, 0, 0, 0, toronto), time.Date(2024, 3, 31, 9, 0, 0, 0, toronto), "1 mon 30 days 23:00:00"},
+		// A day across the spring change is still a day
+		{time.Date(2024, 3, 9, 12, 0, 0, 0, toronto), time.Date(2024, 3, 10, 12, 0, 0, 0, toronto), "1 day"},
+		{time.Date(2024, 3, 10, 12, 0, 0, 0, toronto), time.Date(2024, 3, 9, 12, 0, 0, 0, toronto), "-1 days"},
+		{time.Date(2024, 5, 1, 0, 0, 0, 0, toronto), time.Date(2024, 5, 1, 0, 0, 0, 0, toronto), "00:00:00"},
+		// Far beyond the range of a time.Duration
+		{time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), "9998 years 11 mons 30 days 23:59:59"},
+	}
+
+	for _, test := range tests {
+		p := timestamp.Diff(test.start, test.end, nil)
+		is.Equal(timestamp.FormatPostgresInterval(p, timestamp.IntervalPostgres), test.expected) // Period should match
+		if test.start.Before(test.end) {
+			is.True(p.AddTo(test.start, nil).Equal(test.end)) // Adding the period should give the end
+		}
+	}
+
+	// The location decides where the days fall
+	start := time.Date(2024, 1, 31, 3, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 29, 3, 0, 0, 0, time.UTC)
+	is.Equal(timestamp.Diff(start, end, time.UTC).String(), "P1M")
+	is.Equal(timestamp.Diff(start, end, toronto).String(), "P29D")
+}
+
+func TestDiffIn(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	start := time.Date(2024, 1, 31, 10, 0, 0, 0, toronto)
+
+	tests := []struct {
+		end      time.Time
+		unit     timestamp.Unit
+		expected int64
+	}{
+		{time.Date(2024, 2, 29, 10, 0, 0, 0, toronto), timestamp.Month, 1},
+		{time.Date(2024, 2, 29, 9, 59, 59, 0, toronto), timestamp.Month, 0},
+		{time.Date(2025, 1, 31, 10, 0, 0, 0, toronto), timestamp.Year, 1},
+		{time.Date(2024, 4, 30, 10, 0, 0, 0, toronto), timestamp.Quarter, 1},
+		{time.Date(2024, 4, 30, 9, 0, 0, 0, toronto), timestamp.Quarter, 0},
+		{time.Date(2024, 3, 11, 10, 0, 0, 0, toronto), timestamp.Day, 40},
+		{time.Date(2024, 3, 11, 10, 0, 0, 0, toronto), timestamp.Week, 5},
+		{time.Date(2024, 3, 11, 10, 0, 0, 0, toronto), timestamp.Hour, 40*24 - 1},
+		{start.Add(90*time.Second + time.Millisecond), timestamp.Minute, 1},
+		{start.Add(1500 * time.Microsecond), timestamp.Millisecond, 1},
+		{start.Add(-1500 * time.Microsecond), timestamp.Millisecond, -1},
+		{start.Add(-90 * time.Second), timestamp.Minute, -1},
+		{time.Date(2024, 1, 1, 10, 0, 0, 0, toronto), timestamp.Month, 0},
+		{time.Date(2023, 12, 31, 10, 0, 0, 0, toronto), timestamp.Month, -1},
+	}
+
+	for _, test := range tests {
+		n, err := timestamp.DiffIn(start, test.end, test.unit, nil)
+		is.NoErr(err)              // Should count without error
+		is.Equal(n, test.expected) // Count should match
+	}
+
+	// Past the range of a time.Duration
+	far := time.Date(2524, 1, 31, 10, 0, 0, 0, toronto)
+	n, err := timestamp.DiffIn(start, far, timestamp.Microsecond, nil)
+	is.NoErr(err)
+	is.Equal(n, (far.Unix()-start.Unix())*1e6)
+
+	_, err = timestamp.DiffIn(start, time.Date(300000, 1, 1, 0, 0, 0, 0, time.UTC), timestamp.Nanosecond, nil)
+	is.True(err != nil) // Nanoseconds should overflow
+	t.Log(err)
+}
+
+func TestAge(t *testing.T) {
+	is := is.New(t)
+
+	leapling := timestamp.Date{Year: 2000, Month: time.February, Day: 29}
+	born := timestamp.Date{Year: 1990, Month: time.June, Day: 15}
+
+	tests := []struct {
+		birth    timestamp.Date
+		on       timestamp.Date
+		policy   timestamp.LeapDayPolicy
+		expected int
+	}{
+		{born, timestamp.Date{Year: 2024, Month: time.June, Day: 14}, timestamp.LeapDayFebruary28, 33},
+		{born, timestamp.Date{Year: 2024, Month: time.June, Day: 15}, timestamp.LeapDayFebruary28, 34},
+		{leapling, timestamp.Date{Year: 2018, Month: time.February, Day: 28}, timestamp.LeapDayFebruary28, 18},
+		{leapling, timestamp.Date{Year: 2018, Month: time.February, Day: 28}, timestamp.LeapDayMarch1, 17},
+		{leapling, timestamp.Date{Year: 2018, Month: time.March, Day: 1}, timestamp.LeapDayMarch1, 18},
+		{leapling, timestamp.Date{Year: 2020, Month: time.February, Day: 28}, timestamp.LeapDayFebruary28, 19},
+		{leapling, timestamp.Date{Year: 2020, Month: time.February, Day: 29}, timestamp.LeapDayMarch1, 20},
+		{leapling, leapling, timestamp.LeapDayFebruary28, 0},
+	}
+
+	for _, test := range tests {
+		is.Equal(timestamp.Age(test.birth, test.on, test.policy), test.expected) // Age should match
+	}
+}
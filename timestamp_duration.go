@@ -0,0 +1,387 @@
+package timestamp
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/JohnCGriffin/overflow"
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// DurationStyle a style for FormatDuration
+type DurationStyle int
+
+// Duration styles
+const (
+	DurationCompact DurationStyle = iota // 1d12h30m5.5s
+	DurationVerbose                      // 1 day, 12 hours, 30 minutes and 5.5 seconds
+	DurationClock                        // 36:30:05.5
+)
+
+// durationError make an error pointing at a position in a duration
+func durationError(caller, reason string, value string, pos int) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S(caller).S(": ").S(reason)
+	if pos >= 0 && pos < len(value) {
+		xfmtBuf.S(" '").S(value[pos:]).S("'").C('@').D(pos)
+	}
+	xfmtBuf.S(" in input ").S(value)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// durationUnit get the length of a unit named in a duration. Days are 24
+// hours and weeks 7 days. Months and larger have no fixed length.
+func durationUnit(name string) (length time.Duration, ok bool) {
+	// Go accepts the Greek mu as well as the micro sign
+	if name == "μs" {
+		return time.Microsecond, true
+	}
+	unit, ok := ParseUnit(name)
+	if !ok {
+		return 0, false
+	}
+	switch unit {
+	case Day:
+		return 24 * time.Hour, true
+	case Week:
+		return 7 * 24 * time.Hour, true
+	}
+	length = unit.Duration()
+
+	return length, length != 0
+}
+
+// durationSign get the sign of a duration, -1 when negative and 1 otherwise
+func durationSign(negative bool) int64 {
+	if negative {
+		return -1
+	}
+	return 1
+}
+
+// appendDigit append a decimal digit to a number carrying a sign, with false
+// on overflow
+func appendDigit(n int64, digit byte, sign int64) (int64, bool) {
+	n, ok := overflow.Mul64(n, 10)
+	if !ok {
+		return n, false
+	}
+	return overflow.Add64(n, sign*int64(digit-'0'))
+}
+
+// ParseDuration parse a duration. Everything time.ParseDuration accepts is
+// accepted, along with days (d) and weeks (w), words and clock style values:
+//
+//	1d12h, 2w, 1.5h, -90s
+//	1 day, 2 hours and 5 minutes
+//	3 weeks 2 days
+//	36:15:00, 1:02:03.500, 1:30
+//
+// Days are always 24 hours and weeks 7 days. Months and years have no fixed
+// length and are an error, as is a number without a unit other than 0. A
+// clock value is hours:minutes or hours:minutes:seconds, where hours may be
+// above 23 and seconds may have a fraction. A result that doesn't fit in a
+// time.Duration is an error.
+func ParseDuration(value string) (d time.Duration, err error) {
+	const caller = "timestamp.ParseDuration"
+	s := strings.TrimSpace(value)
+	offset := len(value) - len(strings.TrimLeft(value, " \t\n\r"))
+
+	negative := false
+	if s != "" && (s[0] == '-' || s[0] == '+') {
+		negative = s[0] == '-'
+		s = s[1:]
+		offset++
+	}
+	if s == "" {
+		return 0, durationError(caller, "empty duration", value, -1)
+	}
+
+	// Values are built up with their sign rather than negated at the end, as
+	// the minimum duration has no positive counterpart
+	if strings.IndexByte(s, ':') >= 0 {
+		return parseClockDuration(caller, s, value, offset, negative)
+	}
+	return parseUnitDuration(s, value, offset, negative)
+}
+
+// parseUnitDuration parse a list of numbers and units. Positions in errors
+// are relative to value with s starting at offset.
+func parseUnitDuration(s string, value string, offset int, negative bool) (d time.Duration, err error) {
+	const caller = "timestamp.ParseDuration"
+	if s == "0" {
+		return 0, nil
+	}
+
+	sign := durationSign(negative)
+	var total int64
+	found := false
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == ',' || c == '\t':
+			i++
+			continue
+		case strings.HasPrefix(s[i:], "and ") && found:
+			i += len("and ")
+			continue
+		case (c < '0' || c > '9') && c != '.':
+			return 0, durationError(caller, "expected a number", value, offset+i)
+		}
+
+		// The number
+		start := i
+		var whole int64
+		ok := true
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			if whole, ok = appendDigit(whole, s[i], sign); !ok {
+				return 0, durationError(caller, "number too large", value, offset+start)
+			}
+			i++
+		}
+		fraction := ""
+		if i < len(s) && s[i] == '.' {
+			i++
+			fracStart := i
+			for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+				i++
+			}
+			fraction = s[fracStart:i]
+		}
+		if i-start == 1 && s[start] == '.' {
+			return 0, durationError(caller, "expected a number", value, offset+start)
+		}
+
+		// The unit, possibly after a space
+		for i < len(s) && s[i] == ' ' {
+			i++
+		}
+		unitStart := i
+		for i < len(s) && s[i] != ' ' && s[i] != ',' && s[i] != '.' && (s[i] < '0' || s[i] > '9') {
+			i++
+		}
+		if unitStart == i {
+			return 0, durationError(caller, "missing unit", value, offset+start)
+		}
+		length, ok := durationUnit(s[unitStart:i])
+		if !ok {
+			return 0, durationError(caller, "unknown or calendar unit", value, offset+unitStart)
+		}
+
+		part, ok := overflow.Mul64(whole, int64(length))
+		if !ok {
+			return 0, durationError(caller, "duration too large", value, offset+start)
+		}
+		// The fraction of the unit, worked from the last digit to the first
+		// so each step stays below ten lengths and truncates only once
+		var fracPart int64
+		for k := len(fraction) - 1; k >= 0; k-- {
+			fracPart = (fracPart + int64(fraction[k]-'0')*int64(length)) / 10
+		}
+		sum, overflows := DurationOverflows(time.Duration(total), time.Duration(part), time.Duration(sign*fracPart))
+		if overflows {
+			return 0, durationError(caller, "duration too large", value, offset+start)
+		}
+		total = sum
+		found = true
+	}
+	if !found {
+		return 0, durationError(caller, "no duration found", value, -1)
+	}
+
+	return time.Duration(total), nil
+}
+
+// parseClockDuration parse hours:minutes or hours:minutes:seconds
+func parseClockDuration(caller, s string, value string, offset int, negative bool) (d time.Duration, err error) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return 0, durationError(caller, "too many parts in clock duration", value, offset)
+	}
+
+	sign := durationSign(negative)
+	pos := offset
+	var total int64
+	lengths := [...]time.Duration{time.Hour, time.Minute, time.Second}
+	for n, part := range parts {
+		text := part
+		nsec := 0
+		if n == 2 {
+			if i := strings.IndexByte(part, '.'); i >= 0 {
+				digits := part[i+1:]
+				if len(digits) == 0 || len(digits) > 9 || !isDigits(digits) {
+					return 0, durationError(caller, "bad fraction of a second", value, pos+i)
+				}
+				nsec, _ = atoiDigits(digits)
+				for k := len(digits); k < 9; k++ {
+					nsec *= 10
+				}
+				text = part[:i]
+			}
+		}
+		if !isDigits(text) || (n > 0 && len(text) != 2) {
+			return 0, durationError(caller, "bad clock duration part", value, pos)
+		}
+		var v int64
+		ok := true
+		for i := 0; i < len(text) && ok; i++ {
+			v, ok = appendDigit(v, text[i], sign)
+		}
+		if !ok {
+			return 0, durationError(caller, "duration too large", value, pos)
+		}
+		if n > 0 && sign*v > 59 {
+			return 0, durationError(caller, "minutes and seconds must be 00 to 59", value, pos)
+		}
+		length, ok := overflow.Mul64(v, int64(lengths[n]))
+		if !ok {
+			return 0, durationError(caller, "duration too large", value, pos)
+		}
+		sum, overflows := DurationOverflows(time.Duration(total), time.Duration(length), time.Duration(sign*int64(nsec)))
+		if overflows {
+			return 0, durationError(caller, "duration too large", value, pos)
+		}
+		total = sum
+		pos += len(part) + 1
+	}
+	if len(parts) < 2 {
+		return 0, durationError(caller, "bad clock duration", value, offset)
+	}
+
+	return time.Duration(total), nil
+}
+
+// FormatDuration write a duration in a style. Days are 24 hours and seconds
+// keep any fraction with trailing zeros removed.
+//
+//	DurationCompact  1d12h30m5.5s, 0s, 250ms
+//	DurationVerbose  1 day, 12 hours, 30 minutes and 5.5 seconds
+//	DurationClock    36:30:05.5
+//
+// Compact durations under a second are written as time.Duration.String
+// writes them. All styles can be read back with ParseDuration.
+func FormatDuration(d time.Duration, style DurationStyle) string {
+	xfmtBuf := make(xfmt.Buffer, 0, 32)
+	xfmtBuf = appendDuration(xfmtBuf, d, style)
+
+	return string(xfmtBuf.Bytes())
+}
+
+// appendDuration append a duration in a style
+func appendDuration(b []byte, d time.Duration, style DurationStyle) []byte {
+	// Work with the magnitude as an unsigned value so the minimum duration
+	// can be negated
+	u := uint64(d)
+	if d < 0 {
+		b = append(b, '-')
+		u = -u
+	}
+
+	if style == DurationCompact && u < uint64(time.Second) {
+		// Go's forms for ms, µs and ns are already compact
+		s := time.Duration(u).String()
+		return append(b, s...)
+	}
+
+	nsec := int(u % uint64(time.Second))
+	secs := u / uint64(time.Second)
+	days, hours := secs/86400, int(secs/3600%24)
+	minutes, seconds := int(secs/60%60), int(secs%60)
+
+	switch style {
+	case DurationClock:
+		b = appendUint(b, days*24+uint64(hours))
+		b = append(b, ':')
+		b = appendTwoDigits(b, minutes, false)
+		b = append(b, ':')
+		b = appendTwoDigits(b, seconds, false)
+		return appendFraction(b, nsec, 9, true, ".")
+
+	case DurationVerbose:
+		type part struct {
+			n    uint64
+			name string
+		}
+		parts := [...]part{{days, "day"}, {uint64(hours), "hour"}, {uint64(minutes), "minute"}}
+		count := 0
+		for _, p := range parts {
+			if p.n > 0 {
+				count++
+			}
+		}
+		if seconds > 0 || nsec > 0 || count == 0 {
+			count++
+		}
+		written := 0
+		separator := func() {
+			switch {
+			case written == 0:
+			case written == count-1:
+				b = append(b, " and "...)
+			default:
+				b = append(b, ", "...)
+			}
+			written++
+		}
+		for _, p := range parts {
+			if p.n == 0 {
+				continue
+			}
+			separator()
+			b = appendUint(b, p.n)
+			b = append(b, ' ')
+			b = append(b, p.name...)
+			if p.n != 1 {
+				b = append(b, 's')
+			}
+		}
+		if written < count {
+			separator()
+			b = appendUint(b, uint64(seconds))
+			b = appendFraction(b, nsec, 9, true, ".")
+			b = append(b, " second"...)
+			if seconds != 1 || nsec != 0 {
+				b = append(b, 's')
+			}
+		}
+		return b
+	}
+
+	if days > 0 {
+		b = appendUint(b, days)
+		b = append(b, 'd')
+	}
+	if hours > 0 {
+		b = appendInt(b, hours, 0, 0)
+		b = append(b, 'h')
+	}
+	if minutes > 0 {
+		b = appendInt(b, minutes, 0, 0)
+		b = append(b, 'm')
+	}
+	if seconds > 0 || nsec > 0 {
+		b = appendInt(b, seconds, 0, 0)
+		b = appendFraction(b, nsec, 9, true, ".")
+		b = append(b, 's')
+	}
+
+	return b
+}
+
+// appendUint append an unsigned value
+func appendUint(b []byte, v uint64) []byte {
+	var digits [20]byte
+	i := len(digits)
+	for v >= 10 {
+		i--
+		digits[i] = byte('0' + v%10)
+		v /= 10
+	}
+	i--
+	digits[i] = byte('0' + v)
+
+	return append(b, digits[i:]...)
+}
@@ -0,0 +1,110 @@
+package timestamp_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestParseDuration(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"90s", 90 * time.Second},
+		{"-1.5h", -90 * time.Minute},
+		{"1d12h", 36 * time.Hour},
+		{"2w", 14 * 24 * time.Hour},
+		{"1h30m15.5s", time.Hour + 30*time.Minute + 15500*time.Millisecond},
+		{"250ms", 250 * time.Millisecond},
+		{"3μs", 3 * time.Microsecond},
+		{"1 day, 2 hours and 5 minutes", 26*time.Hour + 5*time.Minute},
+		{"3 weeks 2 days", 23 * 24 * time.Hour},
+		{" 1 hr 5 mins ", time.Hour + 5*time.Minute},
+		{"36:15:00", 36*time.Hour + 15*time.Minute},
+		{"1:02:03.500", time.Hour + 2*time.Minute + 3500*time.Millisecond},
+		{"-1:30", -90 * time.Minute},
+		{"2562047h47m16.854775807s", math.MaxInt64},
+		{"-2562047h47m16.854775808s", math.MinInt64},
+		{"-2562047:47:16.854775808", math.MinInt64},
+		{"0.333333333333s", 333333333 * time.Nanosecond},
+		{"1.000000001w", 7*24*time.Hour + 604800*time.Nanosecond},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.ParseDuration(test.input)
+		is.NoErr(err)                // Should parse without error
+		is.Equal(got, test.expected) // Duration should match
+		t.Logf("%q got %v", test.input, got)
+	}
+}
+
+func TestParseDurationErrors(t *testing.T) {
+	is := is.New(t)
+
+	inputs := []string{
+		"",
+		"5",
+		"1 month",
+		"2y",
+		"3 fortnights",
+		"1:60",
+		"1:2:03",
+		"1:02:03:04",
+		"1:02:03.",
+		"and 5 minutes",
+		"2562048h",
+		"15251w",
+		"99999999999999999999s",
+		"2562047h47m16.854775808s",
+		"-2562047h47m16.854775809s",
+	}
+
+	for _, input := range inputs {
+		_, err := timestamp.ParseDuration(input)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		d        time.Duration
+		style    timestamp.DurationStyle
+		expected string
+	}{
+		{36 * time.Hour, timestamp.DurationCompact, "1d12h"},
+		{36*time.Hour + 30*time.Minute + 5500*time.Millisecond, timestamp.DurationCompact, "1d12h30m5.5s"},
+		{0, timestamp.DurationCompact, "0s"},
+		{250 * time.Millisecond, timestamp.DurationCompact, "250ms"},
+		{-90 * time.Minute, timestamp.DurationCompact, "-1h30m"},
+		{36*time.Hour + 30*time.Minute + 5500*time.Millisecond, timestamp.DurationVerbose, "1 day, 12 hours, 30 minutes and 5.5 seconds"},
+		{26*time.Hour + 5*time.Minute, timestamp.DurationVerbose, "1 day, 2 hours and 5 minutes"},
+		{time.Second, timestamp.DurationVerbose, "1 second"},
+		{0, timestamp.DurationVerbose, "0 seconds"},
+		{36*time.Hour + 15*time.Minute, timestamp.DurationClock, "36:15:00"},
+		{time.Hour + 2*time.Minute + 3500*time.Millisecond, timestamp.DurationClock, "1:02:03.5"},
+		{math.MinInt64, timestamp.DurationClock, "-2562047:47:16.854775808"},
+	}
+
+	for _, test := range tests {
+		got := timestamp.FormatDuration(test.d, test.style)
+		is.Equal(got, test.expected) // Output should match
+		t.Logf("%v got %q", test.d, got)
+
+		if test.d == math.MinInt64 {
+			continue
+		}
+		back, err := timestamp.ParseDuration(got)
+		is.NoErr(err)          // Output should parse
+		is.Equal(back, test.d) // Output should round trip
+	}
+}
@@ -0,0 +1,281 @@
+package timestamp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// RetailAnchor how a 52/53 week retail year finds the day it ends on
+type RetailAnchor int
+
+// Retail year anchors
+const (
+	RetailLastWeekday    RetailAnchor = iota + 1 // the last weekday in the end month, such as the last Saturday of January
+	RetailNearestWeekday                         // the weekday nearest the end of the end month, such as the Saturday nearest January 31
+)
+
+// FiscalPattern the number of weeks in each of the three periods of a retail
+// quarter
+type FiscalPattern int
+
+// Retail period patterns
+const (
+	Pattern445 FiscalPattern = iota + 1 // 4, 4 then 5 weeks
+	Pattern454                          // 4, 5 then 4 weeks
+	Pattern544                          // 5, 4 then 4 weeks
+)
+
+var patternWeeks = [...][3]int{
+	Pattern445: {4, 4, 5},
+	Pattern454: {4, 5, 4},
+	Pattern544: {5, 4, 4},
+}
+
+// FiscalDate a day in a fiscal calendar. Periods number 1 to 12 through the
+// year and three make a quarter. Weeks number from 1 at the start of the year
+// and Day is the day within the period, starting at 1.
+type FiscalDate struct {
+	Year    int
+	Quarter int
+	Period  int
+	Week    int
+	Day     int
+}
+
+// FiscalCalendar a calendar of fiscal years, quarters, periods and weeks in a
+// location. A calendar either follows the months, with a fiscal year starting
+// on the first of a month, or is a 52/53 week retail calendar whose years end
+// on a weekday near the end of a month and whose quarters are 13 weeks split
+// into periods by a pattern such as 4-4-5. A retail year of 53 weeks gives
+// the extra week to the last period.
+type FiscalCalendar struct {
+	location *time.Location
+	endMonth time.Month
+	weekday  time.Weekday
+	anchor   RetailAnchor  // 0 for a calendar following the months
+	pattern  FiscalPattern // weeks in the periods of a retail quarter
+	offset   int           // years between the name of a fiscal year and the year it ends in
+}
+
+// NewFiscalCalendar make a fiscal calendar whose years start on the first of
+// a month, such as time.October for the US federal government. Periods are
+// calendar months. A fiscal year is named for the calendar year it ends in,
+// so with an October start fiscal 2024 begins on October 1, 2023. A nil
+// location uses UTC.
+func NewFiscalCalendar(startMonth time.Month, location *time.Location) *FiscalCalendar {
+	if location == nil {
+		location = time.UTC
+	}
+	endMonth := startMonth - 1
+	if startMonth <= time.January || startMonth > time.December {
+		endMonth = time.December
+	}
+
+	return &FiscalCalendar{location: location, endMonth: endMonth}
+}
+
+// NewRetailCalendar make a 52/53 week retail calendar whose years end on a
+// weekday in or near the end of a month. A fiscal year is named for the
+// calendar year it ends in. The National Retail Federation calendar ends on
+// the Saturday nearest January 31, uses the 4-5-4 pattern and names years
+// for the year before, so its fiscal 2023 runs from January 29, 2023 to
+// February 3, 2024:
+//
+//	timestamp.NewRetailCalendar(time.January, time.Saturday, timestamp.RetailNearestWeekday, timestamp.Pattern454, location).NamedForStartYear()
+//
+// A nil location uses UTC.
+func NewRetailCalendar(endMonth time.Month, weekday time.Weekday, anchor RetailAnchor, pattern FiscalPattern, location *time.Location) *FiscalCalendar {
+	if location == nil {
+		location = time.UTC
+	}
+	if anchor != RetailNearestWeekday {
+		anchor = RetailLastWeekday
+	}
+	if pattern < Pattern445 || pattern > Pattern544 {
+		pattern = Pattern445
+	}
+
+	return &FiscalCalendar{location: location, endMonth: endMonth, weekday: weekday, anchor: anchor, pattern: pattern}
+}
+
+// Location get the location of the calendar
+func (c *FiscalCalendar) Location() *time.Location {
+	return c.location
+}
+
+// NamedForStartYear get a copy of the calendar that names each fiscal year for
+// the calendar year before the one it ends in. This suits years that end
+// early in a calendar year, such as retail years ending in January.
+func (c *FiscalCalendar) NamedForStartYear() *FiscalCalendar {
+	named := *c
+	named.offset = 1
+	return &named
+}
+
+// end get the last day of the fiscal year ending in a calendar year
+func (c *FiscalCalendar) end(endYear int) Date {
+	last := Date{Year: endYear, Month: c.endMonth, Day: daysIn(c.endMonth, endYear)}
+	switch c.anchor {
+	case RetailLastWeekday:
+		return last.AddDays(-(int(last.Weekday()) - int(c.weekday) + 7) % 7)
+	case RetailNearestWeekday:
+		// The nearest weekday is at most three days either side
+		return last.AddDays((int(c.weekday)-int(last.Weekday())+10)%7 - 3)
+	}
+	return last
+}
+
+// start get the first day of the fiscal year ending in a calendar year
+func (c *FiscalCalendar) start(endYear int) Date {
+	return c.end(endYear - 1).AddDays(1)
+}
+
+// endYear get the calendar year the fiscal year with a name ends in
+func (c *FiscalCalendar) endYear(year int) int {
+	return year + c.offset
+}
+
+// name get the name of the fiscal year ending in a calendar year
+func (c *FiscalCalendar) name(endYear int) int {
+	return endYear - c.offset
+}
+
+// Weeks get the number of weeks in a fiscal year, which is 52 or 53 for a
+// retail calendar. For a calendar following the months the last week is
+// short.
+func (c *FiscalCalendar) Weeks(year int) int {
+	endYear := c.endYear(year)
+	days := c.end(endYear).DaysSince(c.start(endYear)) + 1
+	return (days + 6) / 7
+}
+
+// periodDates get the first day of a period and the first day after it
+func (c *FiscalCalendar) periodDates(endYear, period int) (first, next Date) {
+	start := c.start(endYear)
+	if c.anchor == 0 {
+		return start.AddMonths(period - 1), start.AddMonths(period)
+	}
+
+	weeks := patternWeeks[c.pattern]
+	before := (period - 1) / 3 * 13
+	for n := 0; n < (period-1)%3; n++ {
+		before += weeks[n]
+	}
+	first = start.AddDays(7 * before)
+	next = first.AddDays(7 * weeks[(period-1)%3])
+	if period == 12 {
+		next = c.end(endYear).AddDays(1)
+	}
+
+	return first, next
+}
+
+// fiscalError make an error for a fiscal field out of range
+func fiscalError(caller, field string, value, year int) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S(caller).S(": ").S(field).S(" ").D(value).S(" out of range for fiscal year ").D(year)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// FiscalDateOf get the fiscal fields of the date of t in the calendar's
+// location
+func (c *FiscalCalendar) FiscalDateOf(t time.Time) FiscalDate {
+	d := DateOf(t.In(c.location))
+
+	endYear := d.Year
+	if d.After(c.end(endYear)) {
+		endYear++
+	} else if d.Before(c.start(endYear)) {
+		endYear--
+	}
+
+	f := FiscalDate{Year: c.name(endYear), Week: d.DaysSince(c.start(endYear))/7 + 1}
+	for f.Period = 1; f.Period < 12; f.Period++ {
+		if _, next := c.periodDates(endYear, f.Period); d.Before(next) {
+			break
+		}
+	}
+	first, _ := c.periodDates(endYear, f.Period)
+	f.Quarter, f.Day = (f.Period-1)/3+1, d.DaysSince(first)+1
+
+	return f
+}
+
+// Date get the date of a fiscal day from its year, period and day in the
+// period. The quarter and week are not used.
+func (c *FiscalCalendar) Date(f FiscalDate) (Date, error) {
+	if f.Period < 1 || f.Period > 12 {
+		return Date{}, fiscalError("timestamp.FiscalCalendar.Date", "period", f.Period, f.Year)
+	}
+	first, next := c.periodDates(c.endYear(f.Year), f.Period)
+	d := first.AddDays(f.Day - 1)
+	if f.Day < 1 || !d.Before(next) {
+		return Date{}, fiscalError("timestamp.FiscalCalendar.Date", "day", f.Day, f.Year)
+	}
+
+	return d, nil
+}
+
+// Time get the first instant of a fiscal day in the calendar's location,
+// which is midnight unless a daylight saving change skips it
+func (c *FiscalCalendar) Time(f FiscalDate) (time.Time, error) {
+	d, err := c.Date(f)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return firstInstant(d, c.location), nil
+}
+
+// span get the interval from the first instant of first to the first instant
+// of next
+func (c *FiscalCalendar) span(first, next Date) Interval {
+	return Interval{Start: firstInstant(first, c.location), End: firstInstant(next, c.location)}
+}
+
+// YearSpan get the fiscal year as a half-open interval in the calendar's
+// location
+func (c *FiscalCalendar) YearSpan(year int) Interval {
+	endYear := c.endYear(year)
+	return c.span(c.start(endYear), c.end(endYear).AddDays(1))
+}
+
+// QuarterSpan get a quarter of a fiscal year, 1 to 4, as a half-open interval
+func (c *FiscalCalendar) QuarterSpan(year, quarter int) (Interval, error) {
+	if quarter < 1 || quarter > 4 {
+		return Interval{}, fiscalError("timestamp.FiscalCalendar.QuarterSpan", "quarter", quarter, year)
+	}
+	endYear := c.endYear(year)
+	first, _ := c.periodDates(endYear, quarter*3-2)
+	_, next := c.periodDates(endYear, quarter*3)
+
+	return c.span(first, next), nil
+}
+
+// PeriodSpan get a period of a fiscal year, 1 to 12, as a half-open interval
+func (c *FiscalCalendar) PeriodSpan(year, period int) (Interval, error) {
+	if period < 1 || period > 12 {
+		return Interval{}, fiscalError("timestamp.FiscalCalendar.PeriodSpan", "period", period, year)
+	}
+
+	return c.span(c.periodDates(c.endYear(year), period)), nil
+}
+
+// WeekSpan get a week of a fiscal year as a half-open interval. For a
+// calendar following the months the last week ends with the year.
+func (c *FiscalCalendar) WeekSpan(year, week int) (Interval, error) {
+	if week < 1 || week > c.Weeks(year) {
+		return Interval{}, fiscalError("timestamp.FiscalCalendar.WeekSpan", "week", week, year)
+	}
+	endYear := c.endYear(year)
+	first := c.start(endYear).AddDays(7 * (week - 1))
+	next := first.AddDays(7)
+	if end := c.end(endYear).AddDays(1); end.Before(next) {
+		next = end
+	}
+
+	return c.span(first, next), nil
+}
@@ -0,0 +1,98 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestFiscalCalendar(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	nrf := timestamp.NewRetailCalendar(time.January, time.Saturday, timestamp.RetailNearestWeekday, timestamp.Pattern454, toronto).NamedForStartYear()
+	lastSaturday := timestamp.NewRetailCalendar(time.January, time.Saturday, timestamp.RetailLastWeekday, timestamp.Pattern445, toronto)
+	october := timestamp.NewFiscalCalendar(time.October, toronto)
+
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, toronto)
+	}
+
+	tests := []struct {
+		c        *timestamp.FiscalCalendar
+		t        time.Time
+		expected timestamp.FiscalDate
+	}{
+		{nrf, day(2023, 1, 29), timestamp.FiscalDate{Year: 2023, Quarter: 1, Period: 1, Week: 1, Day: 1}},
+		{nrf, day(2023, 1, 28), timestamp.FiscalDate{Year: 2022, Quarter: 4, Period: 12, Week: 52, Day: 28}},
+		{nrf, day(2023, 2, 26), timestamp.FiscalDate{Year: 2023, Quarter: 1, Period: 2, Week: 5, Day: 1}},
+		{nrf, day(2023, 4, 1), timestamp.FiscalDate{Year: 2023, Quarter: 1, Period: 2, Week: 9, Day: 35}},
+		{nrf, day(2024, 2, 3), timestamp.FiscalDate{Year: 2023, Quarter: 4, Period: 12, Week: 53, Day: 35}},
+		{lastSaturday, day(2023, 1, 29), timestamp.FiscalDate{Year: 2024, Quarter: 1, Period: 1, Week: 1, Day: 1}},
+		{lastSaturday, day(2023, 3, 26), timestamp.FiscalDate{Year: 2024, Quarter: 1, Period: 3, Week: 9, Day: 1}},
+		{lastSaturday, day(2024, 1, 28), timestamp.FiscalDate{Year: 2025, Quarter: 1, Period: 1, Week: 1, Day: 1}},
+		{october, day(2023, 10, 1), timestamp.FiscalDate{Year: 2024, Quarter: 1, Period: 1, Week: 1, Day: 1}},
+		{october, day(2024, 2, 29), timestamp.FiscalDate{Year: 2024, Quarter: 2, Period: 5, Week: 22, Day: 29}},
+		{october, day(2024, 9, 30), timestamp.FiscalDate{Year: 2024, Quarter: 4, Period: 12, Week: 53, Day: 30}},
+		// The date is taken in the calendar's location
+		{october, time.Date(2023, 10, 1, 2, 0, 0, 0, time.UTC), timestamp.FiscalDate{Year: 2023, Quarter: 4, Period: 12, Week: 53, Day: 30}},
+	}
+
+	for _, test := range tests {
+		got := test.c.FiscalDateOf(test.t)
+		is.Equal(got, test.expected) // Fiscal date should match
+
+		back, err := test.c.Time(got)
+		is.NoErr(err)
+		is.Equal(back, time.Date(test.t.In(toronto).Year(), test.t.In(toronto).Month(), test.t.In(toronto).Day(), 0, 0, 0, 0, toronto)) // Should map back to the day
+	}
+
+	is.Equal(nrf.Weeks(2022), 52)
+	is.Equal(nrf.Weeks(2023), 53)
+	is.Equal(october.Weeks(2024), 53)
+
+	span := nrf.YearSpan(2023)
+	is.Equal(span.String(), "2023-01-29T00:00:00-05:00/2024-02-04T00:00:00-05:00")
+	span, err = nrf.QuarterSpan(2023, 2)
+	is.NoErr(err)
+	is.Equal(span.String(), "2023-04-30T00:00:00-04:00/2023-07-30T00:00:00-04:00")
+	span, err = nrf.PeriodSpan(2023, 12)
+	is.NoErr(err)
+	is.Equal(span.Duration(), 5*7*24*time.Hour)
+	span, err = nrf.WeekSpan(2023, 53)
+	is.NoErr(err)
+	is.Equal(span.String(), "2024-01-28T00:00:00-05:00/2024-02-04T00:00:00-05:00")
+	span, err = october.PeriodSpan(2024, 5)
+	is.NoErr(err)
+	is.Equal(span.String(), "2024-02-01T00:00:00-05:00/2024-03-01T00:00:00-05:00")
+	span, err = october.WeekSpan(2024, 53)
+	is.NoErr(err)
+	is.Equal(span.Duration(), 48*time.Hour)
+
+	// Every day maps to fiscal fields and back
+	for _, c := range []*timestamp.FiscalCalendar{nrf, lastSaturday, october} {
+		for d := day(2019, 1, 1); d.Year() < 2027; d = d.AddDate(0, 0, 1) {
+			f := c.FiscalDateOf(d)
+			back, err := c.Date(f)
+			is.NoErr(err)
+			is.Equal(back, timestamp.DateOf(d)) // Should round trip
+			is.True(f.Week >= 1 && f.Week <= c.Weeks(f.Year))
+		}
+	}
+
+	for _, f := range []timestamp.FiscalDate{{Year: 2023, Period: 13, Day: 1}, {Year: 2023, Period: 1, Day: 0}, {Year: 2023, Period: 2, Day: 36}} {
+		_, err := nrf.Date(f)
+		is.True(err != nil) // Should be out of range
+		t.Log(err)
+	}
+	_, err = nrf.QuarterSpan(2023, 5)
+	is.True(err != nil)
+	_, err = nrf.WeekSpan(2022, 53)
+	is.True(err != nil)
+	_, err = nrf.PeriodSpan(2022, 0)
+	is.True(err != nil)
+}
@@ -0,0 +1,559 @@
+package timestamp
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// HolidayKind how a HolidayRule finds its date in a year
+type HolidayKind int
+
+// Kinds of holiday rule
+const (
+	HolidayFixed          HolidayKind = iota + 1 // a fixed month and day
+	HolidayWeekday                               // the nth or last weekday of a month
+	HolidayEaster                                // days from Western Easter
+	HolidayOrthodoxEaster                        // days from Orthodox Easter
+)
+
+// Observance which day a holiday falling on a Saturday or Sunday is observed
+type Observance int
+
+// Observances
+const (
+	ObserveActual  Observance = iota // on the day itself
+	ObserveMonday                    // on the following Monday, or the next free weekday
+	ObserveNearest                   // on Friday for a Saturday, Monday for a Sunday
+)
+
+var observanceNames = [...]string{
+	ObserveActual:  "actual",
+	ObserveMonday:  "monday",
+	ObserveNearest: "nearest",
+}
+
+// ordinalNames the words for the nth weekday of a month
+var ordinalNames = [...]string{"last", "first", "second", "third", "fourth", "fifth"}
+
+// HolidayRule a rule giving the date of a holiday in any year. From and Until
+// limit the years the rule applies to and are ignored when 0.
+//
+// Rules can be written as text, which is what String gives and
+// ParseHolidayRules reads:
+//
+//	New Year's Day: 01-01 observed nearest
+//	Family Day: third monday of february from 2008
+//	Good Friday: easter -2
+//	Orthodox Easter Monday: orthodox easter +1
+//	Memorial Day: last monday of may
+type HolidayRule struct {
+	Name     string
+	Kind     HolidayKind
+	Month    time.Month   // month of a fixed or weekday rule
+	Day      int          // day of a fixed rule
+	Weekday  time.Weekday // weekday of a weekday rule
+	Nth      int          // 1 to 5 for the nth weekday or -1 for the last
+	Offset   int          // days after Easter, negative for before
+	Observed Observance
+	From     int // first year the rule applies
+	Until    int // last year the rule applies
+}
+
+// Holiday a holiday in a year
+type Holiday struct {
+	Name     string
+	Date     Date // the day the holiday is observed
+	Actual   Date // the day given by the rule
+	Observed bool // is Date moved from Actual
+}
+
+// Easter get the date of Western Easter Sunday in the Gregorian calendar
+func Easter(year int) Date {
+	// The anonymous Gregorian algorithm
+	a, b, c := year%19, year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	n := h + l - 7*m + 114
+
+	return Date{Year: year, Month: time.Month(n / 31), Day: n%31 + 1}
+}
+
+// OrthodoxEaster get the date of Orthodox Easter Sunday, which is found in the
+// Julian calendar, as a Gregorian date
+func OrthodoxEaster(year int) Date {
+	// The Meeus Julian algorithm
+	a, b, c := year%4, year%7, year%19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	n := d + e + 114
+	julian := Date{Year: year, Month: time.Month(n / 31), Day: n%31 + 1}
+
+	// The Julian calendar falls a day further behind in each century year
+	// that is not a leap year in the Gregorian calendar
+	return julian.AddDays(year/100 - year/400 - 2)
+}
+
+// AppliesIn does the rule apply in a year
+func (r HolidayRule) AppliesIn(year int) bool {
+	return (r.From == 0 || year >= r.From) && (r.Until == 0 || year <= r.Until)
+}
+
+// Date get the date the rule gives in a year before any observance is
+// applied. False is returned if the rule does not apply in the year or there
+// is no such day, such as a fifth Monday in a month with four.
+func (r HolidayRule) Date(year int) (Date, bool) {
+	if !r.AppliesIn(year) {
+		return Date{}, false
+	}
+
+	switch r.Kind {
+	case HolidayFixed:
+		d := Date{Year: year, Month: r.Month, Day: r.Day}
+		return d, d.IsValid()
+	case HolidayWeekday:
+		if r.Month < time.January || r.Month > time.December || r.Nth < -1 || r.Nth == 0 || r.Nth > 5 {
+			return Date{}, false
+		}
+		if r.Nth < 0 {
+			last := Date{Year: year, Month: r.Month, Day: daysIn(r.Month, year)}
+			return last.AddDays(-(int(last.Weekday()) - int(r.Weekday) + 7) % 7), true
+		}
+		first := Date{Year: year, Month: r.Month, Day: 1}
+		d := first.AddDays((int(r.Weekday)-int(first.Weekday())+7)%7 + (r.Nth-1)*7)
+		return d, d.Month == r.Month
+	case HolidayEaster:
+		return Easter(year).AddDays(r.Offset), true
+	case HolidayOrthodoxEaster:
+		return OrthodoxEaster(year).AddDays(r.Offset), true
+	}
+
+	return Date{}, false
+}
+
+// observe get the day a holiday on a date is observed, skipping days that are
+// taken when moving forward to a Monday
+func (r HolidayRule) observe(d Date, taken map[Date]bool) Date {
+	weekday := d.Weekday()
+	if weekday != time.Saturday && weekday != time.Sunday {
+		return d
+	}
+
+	switch r.Observed {
+	case ObserveNearest:
+		if weekday == time.Saturday {
+			return d.AddDays(-1)
+		}
+		return d.AddDays(1)
+	case ObserveMonday:
+		for d = d.AddDays(1); taken[d] || d.Weekday() == time.Saturday || d.Weekday() == time.Sunday; d = d.AddDays(1) {
+		}
+	}
+
+	return d
+}
+
+// String get the rule in the text form ParseHolidayRules reads
+func (r HolidayRule) String() string {
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S(r.Name).S(": ")
+
+	switch r.Kind {
+	case HolidayFixed:
+		*xfmtBuf = appendInt(*xfmtBuf, int(r.Month), 2, '0')
+		xfmtBuf.C('-')
+		*xfmtBuf = appendInt(*xfmtBuf, r.Day, 2, '0')
+	case HolidayWeekday:
+		if r.Nth >= -1 && r.Nth <= 5 {
+			xfmtBuf.S(ordinalNames[max(r.Nth, 0)])
+		}
+		xfmtBuf.C(' ').S(strings.ToLower(r.Weekday.String())).S(" of ").S(strings.ToLower(r.Month.String()))
+	case HolidayEaster, HolidayOrthodoxEaster:
+		if r.Kind == HolidayOrthodoxEaster {
+			xfmtBuf.S("orthodox ")
+		}
+		xfmtBuf.S("easter")
+		if r.Offset > 0 {
+			xfmtBuf.S(" +").D(r.Offset)
+		} else if r.Offset < 0 {
+			xfmtBuf.S(" ").D(r.Offset)
+		}
+	}
+
+	if r.Observed != ObserveActual && int(r.Observed) < len(observanceNames) {
+		xfmtBuf.S(" observed ").S(observanceNames[r.Observed])
+	}
+	if r.From != 0 {
+		xfmtBuf.S(" from ").D(r.From)
+	}
+	if r.Until != 0 {
+		xfmtBuf.S(" until ").D(r.Until)
+	}
+
+	return BytesToString(xfmtBuf.Bytes()...)
+}
+
+// holidayRuleJSON the JSON form of a rule, with the date rule written as
+// text and the other parts as fields
+type holidayRuleJSON struct {
+	Name     string `json:"name"`
+	Rule     string `json:"rule"`
+	Observed string `json:"observed,omitempty"`
+	From     int    `json:"from,omitempty"`
+	Until    int    `json:"until,omitempty"`
+}
+
+// MarshalJSON write the rule as an object such as
+// {"name":"Christmas Day","rule":"12-25","observed":"monday","from":1990}
+func (r HolidayRule) MarshalJSON() ([]byte, error) {
+	dateRule := r
+	dateRule.Name, dateRule.Observed, dateRule.From, dateRule.Until = "", ObserveActual, 0, 0
+
+	j := holidayRuleJSON{Name: r.Name, Rule: strings.TrimPrefix(dateRule.String(), ": "), From: r.From, Until: r.Until}
+	if r.Observed != ObserveActual && int(r.Observed) < len(observanceNames) {
+		j.Observed = observanceNames[r.Observed]
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON read a rule written by MarshalJSON. The rule field may also
+// carry observed, from and until clauses as in the text form.
+func (r *HolidayRule) UnmarshalJSON(data []byte) error {
+	var j holidayRuleJSON
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	expr := j.Rule
+	if j.Observed != "" {
+		expr += " observed " + j.Observed
+	}
+	rule, err := parseHolidayExpr(strings.TrimSpace(j.Name), expr)
+	if err != nil {
+		return holidayError(err.Error(), "", string(data))
+	}
+	if j.From != 0 {
+		rule.From = j.From
+	}
+	if j.Until != 0 {
+		rule.Until = j.Until
+	}
+	*r = rule
+
+	return nil
+}
+
+// HolidayRules a set of holiday rules, which can be used as the holidays of a
+// WorkCalendar. Observances assume a Saturday and Sunday weekend. The
+// holidays of each year are worked out once and kept, so the rules can't be
+// changed once made. Copies share what has been worked out and are safe for
+// concurrent use.
+type HolidayRules struct {
+	rules []HolidayRule
+	years *sync.Map // holidays worked out for a year, by year
+}
+
+// NewHolidayRules make a set of holiday rules
+func NewHolidayRules(rules ...HolidayRule) HolidayRules {
+	return HolidayRules{rules: slices.Clone(rules), years: new(sync.Map)}
+}
+
+// Rules get a copy of the rules in the set
+func (rules HolidayRules) Rules() []HolidayRule {
+	return slices.Clone(rules.rules)
+}
+
+// MarshalJSON write the rules as the JSON array ParseHolidayRulesJSON reads
+func (rules HolidayRules) MarshalJSON() ([]byte, error) {
+	if rules.rules == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(rules.rules)
+}
+
+// UnmarshalJSON read rules from the JSON array ParseHolidayRulesJSON reads
+func (rules *HolidayRules) UnmarshalJSON(data []byte) error {
+	var list []HolidayRule
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*rules = NewHolidayRules(list...)
+	return nil
+}
+
+// holidayError make an error for a holiday rule definition
+func holidayError(reason string, part string, input string) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.ParseHolidayRules: ").S(reason)
+	if part != "" {
+		xfmtBuf.S(" '").S(part).S("'")
+	}
+	xfmtBuf.S(" in input ").S(input)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// ParseHolidayRules read rules written one per line as a name, a colon and a
+// date rule, followed by optional clauses. Blank lines and lines starting
+// with # are skipped.
+//
+// Date rules:
+//
+//	12-25                          a fixed month and day
+//	third monday of february       the nth weekday of a month, first to fifth
+//	last monday of may             the last weekday of a month
+//	easter, easter -2, easter +1   days from Western Easter
+//	orthodox easter +1             days from Orthodox Easter
+//
+// Clauses:
+//
+//	observed monday                move a weekend holiday to the next free weekday
+//	observed nearest               move a Saturday holiday to Friday and a Sunday one to Monday
+//	from 2008, until 2020          limit the years the rule applies to
+func ParseHolidayRules(text string) (HolidayRules, error) {
+	var rules []HolidayRule
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, expr, found := strings.Cut(line, ":")
+		if !found || strings.TrimSpace(name) == "" {
+			return HolidayRules{}, holidayError("rule needs a name and a colon", "", line)
+		}
+		rule, err := parseHolidayExpr(strings.TrimSpace(name), expr)
+		if err != nil {
+			return HolidayRules{}, holidayError(err.Error(), "", line)
+		}
+		rules = append(rules, rule)
+	}
+
+	return NewHolidayRules(rules...), nil
+}
+
+// ParseHolidayRulesJSON read rules from a JSON array of objects such as
+// {"name": "Good Friday", "rule": "easter -2"} with optional observed, from
+// and until fields
+func ParseHolidayRulesJSON(data []byte) (HolidayRules, error) {
+	var rules HolidayRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return HolidayRules{}, err
+	}
+	return rules, nil
+}
+
+// parseHolidayExpr read a date rule and its clauses. The error gives only the
+// reason and token so the caller can say where it came from.
+func parseHolidayExpr(name, expr string) (HolidayRule, error) {
+	rule := HolidayRule{Name: name}
+	fail := func(reason, token string) (HolidayRule, error) {
+		if token != "" {
+			reason += " '" + token + "'"
+		}
+		return HolidayRule{}, errors.New(reason)
+	}
+
+	tokens := strings.Fields(strings.ToLower(expr))
+	if len(tokens) == 0 {
+		return fail("missing date rule", "")
+	}
+
+	switch {
+	case tokens[0] == "easter" || tokens[0] == "orthodox" && len(tokens) > 1 && tokens[1] == "easter":
+		rule.Kind = HolidayEaster
+		if tokens[0] == "orthodox" {
+			rule.Kind, tokens = HolidayOrthodoxEaster, tokens[1:]
+		}
+		tokens = tokens[1:]
+		if len(tokens) > 0 && (tokens[0][0] == '+' || tokens[0][0] == '-') {
+			offset, err := strconv.Atoi(tokens[0])
+			if err != nil {
+				return fail("invalid offset", tokens[0])
+			}
+			rule.Offset, tokens = offset, tokens[1:]
+		}
+	case len(tokens[0]) == 5 && tokens[0][2] == '-' && isDigits(tokens[0][:2]) && isDigits(tokens[0][3:]):
+		rule.Kind = HolidayFixed
+		month, _ := atoiDigits(tokens[0][:2])
+		day, _ := atoiDigits(tokens[0][3:])
+		rule.Month, rule.Day = time.Month(month), day
+		// 2000 is a leap year so February 29 is allowed
+		if !(Date{Year: 2000, Month: rule.Month, Day: rule.Day}).IsValid() {
+			return fail("invalid month and day", tokens[0])
+		}
+		tokens = tokens[1:]
+	default:
+		if len(tokens) < 4 || tokens[2] != "of" {
+			return fail("unknown date rule", strings.TrimSpace(expr))
+		}
+		rule.Kind = HolidayWeekday
+		rule.Nth = slices.Index(ordinalNames[:], tokens[0])
+		if rule.Nth < 0 {
+			return fail("unknown ordinal", tokens[0])
+		}
+		if rule.Nth == 0 {
+			rule.Nth = -1
+		}
+		weekday, ok := weekdayNamed(tokens[1])
+		if !ok {
+			return fail("unknown weekday", tokens[1])
+		}
+		month, ok := monthNamed(tokens[3])
+		if !ok {
+			return fail("unknown month", tokens[3])
+		}
+		rule.Weekday, rule.Month, tokens = weekday, month, tokens[4:]
+	}
+
+	for len(tokens) > 0 {
+		if len(tokens) < 2 {
+			return fail("clause needs a value", tokens[0])
+		}
+		clause, value := tokens[0], tokens[1]
+		tokens = tokens[2:]
+
+		switch clause {
+		case "observed":
+			observed := slices.Index(observanceNames[:], value)
+			if observed < 0 {
+				return fail("unknown observance", value)
+			}
+			rule.Observed = Observance(observed)
+		case "from", "until":
+			year, err := atoiDigits(value)
+			if !isDigits(value) || err != nil {
+				return fail("invalid year", value)
+			}
+			if clause == "from" {
+				rule.From = year
+			} else {
+				rule.Until = year
+			}
+		default:
+			return fail("unknown clause", clause)
+		}
+	}
+
+	return rule, nil
+}
+
+// weekdayNamed find a weekday by its English name or first three letters
+func weekdayNamed(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// monthNamed find a month by its English name or first three letters
+func monthNamed(name string) (time.Month, bool) {
+	for month := time.January; month <= time.December; month++ {
+		full := strings.ToLower(month.String())
+		if name == full || name == full[:3] {
+			return month, true
+		}
+	}
+	return 0, false
+}
+
+// Holidays get the holidays the rules give for a year, in order of the day
+// they are observed. A holiday moved by its observance may be observed in an
+// adjacent year, as when New Year's Day on a Saturday is observed on the
+// Friday before. Holidays moved to a Monday skip days already taken by other
+// holidays, so Christmas on a Saturday and Boxing Day on a Sunday are observed
+// on Monday and Tuesday.
+func (rules HolidayRules) Holidays(year int) []Holiday {
+	return slices.Clone(rules.holidays(year))
+}
+
+// holidays get the holidays for a year, worked out on first use. The result
+// is shared and must not be changed.
+func (rules HolidayRules) holidays(year int) []Holiday {
+	if rules.years == nil {
+		return rules.workOut(year)
+	}
+	if holidays, ok := rules.years.Load(year); ok {
+		return holidays.([]Holiday)
+	}
+	holidays, _ := rules.years.LoadOrStore(year, rules.workOut(year))
+	return holidays.([]Holiday)
+}
+
+// workOut work out the holidays for a year
+func (rules HolidayRules) workOut(year int) []Holiday {
+	holidays := make([]Holiday, 0, len(rules.rules))
+	indexes := make([]int, 0, len(rules.rules))
+	taken := make(map[Date]bool, len(rules.rules))
+	for n, rule := range rules.rules {
+		if d, ok := rule.Date(year); ok {
+			holidays = append(holidays, Holiday{Name: rule.Name, Date: d, Actual: d})
+			indexes = append(indexes, n)
+			taken[d] = true
+		}
+	}
+
+	// Move holidays in the order they fall so earlier ones get first choice
+	order := make([]int, len(holidays))
+	for n := range order {
+		order[n] = n
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return holidays[a].Actual.Compare(holidays[b].Actual)
+	})
+	for _, n := range order {
+		h := &holidays[n]
+		h.Date = rules.rules[indexes[n]].observe(h.Actual, taken)
+		h.Observed = h.Date != h.Actual
+		taken[h.Date] = true
+	}
+
+	slices.SortStableFunc(holidays, func(a, b Holiday) int {
+		return a.Date.Compare(b.Date)
+	})
+
+	return holidays
+}
+
+// IsHoliday is the date a holiday, either as the day a rule gives or as the
+// day it is observed
+func (rules HolidayRules) IsHoliday(d Date) bool {
+	for year := d.Year - 1; year <= d.Year+1; year++ {
+		for _, h := range rules.holidays(year) {
+			if h.Date == d || h.Actual == d {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HolidaySet get the holidays for a span of years as a fixed set holding the
+// days they are observed and the days the rules give
+func (rules HolidayRules) HolidaySet(from, until int) HolidaySet {
+	set := HolidaySet{}
+	for year := from; year <= until; year++ {
+		for _, h := range rules.holidays(year) {
+			set[h.Date] = h.Name
+			if _, ok := set[h.Actual]; !ok {
+				set[h.Actual] = h.Name
+			}
+		}
+	}
+	return set
+}
@@ -0,0 +1,178 @@
+package timestamp_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+const holidayText = `
+# Holidays for testing
+New Year's Day: 01-01 observed nearest
+Family Day: third monday of february from 2008
+Good Friday: easter -2
+Easter Monday: easter +1
+Orthodox Easter: orthodox easter
+Memorial Day: last Monday of May
+Thanksgiving: fourth thu of nov
+Christmas Day: 12-25 observed monday
+Boxing Day: 12-26 observed monday until 2030
+`
+
+func TestEaster(t *testing.T) {
+	is := is.New(t)
+
+	western := map[int]string{1961: "1961-04-02", 2000: "2000-04-23", 2019: "2019-04-21", 2024: "2024-03-31", 2025: "2025-04-20", 2038: "2038-04-25"}
+	for year, expected := range western {
+		is.Equal(timestamp.Easter(year).String(), expected) // Western Easter should match
+	}
+
+	orthodox := map[int]string{2010: "2010-04-04", 2021: "2021-05-02", 2023: "2023-04-16", 2024: "2024-05-05", 2025: "2025-04-20"}
+	for year, expected := range orthodox {
+		is.Equal(timestamp.OrthodoxEaster(year).String(), expected) // Orthodox Easter should match
+	}
+}
+
+func TestHolidayRules(t *testing.T) {
+	is := is.New(t)
+
+	rules, err := timestamp.ParseHolidayRules(holidayText)
+	is.NoErr(err)
+	is.Equal(len(rules.Rules()), 9)
+
+	var got []string
+	for _, h := range rules.Holidays(2021) {
+		got = append(got, h.Date.String()+" "+h.Name)
+	}
+	is.Equal(got, []string{
+		"2021-01-01 New Year's Day",
+		"2021-02-15 Family Day",
+		"2021-04-02 Good Friday",
+		"2021-04-05 Easter Monday",
+		"2021-05-02 Orthodox Easter",
+		"2021-05-31 Memorial Day",
+		"2021-11-25 Thanksgiving",
+		"2021-12-27 Christmas Day",
+		"2021-12-28 Boxing Day",
+	})
+
+	// New Year's Day on a Saturday is observed the year before
+	holidays := rules.Holidays(2022)
+	is.Equal(holidays[0].Date.String(), "2021-12-31")
+	is.Equal(holidays[0].Actual.String(), "2022-01-01")
+	is.True(holidays[0].Observed)
+
+	// Holidays worked out once are kept and not changed through results
+	holidays[0].Name = "Changed"
+	is.Equal(rules.Holidays(2022)[0].Name, "New Year's Day")
+
+	is.True(rules.IsHoliday(timestamp.Date{Year: 2021, Month: time.December, Day: 31}))
+	is.True(rules.IsHoliday(timestamp.Date{Year: 2022, Month: time.January, Day: 1}))
+	is.True(!rules.IsHoliday(timestamp.Date{Year: 2022, Month: time.January, Day: 3}))
+
+	// Validity ranges
+	is.Equal(len(rules.Holidays(2007)), 8)
+	is.Equal(len(rules.Holidays(2031)), 8)
+	_, ok := rules.Rules()[1].Date(2007)
+	is.True(!ok) // Family Day should not apply before 2008
+
+	fifth := timestamp.HolidayRule{Kind: timestamp.HolidayWeekday, Month: time.February, Weekday: time.Monday, Nth: 5}
+	_, ok = fifth.Date(2024)
+	is.True(!ok) // February 2024 has four Mondays
+	d, ok := fifth.Date(2016)
+	is.True(ok)
+	is.Equal(d.String(), "2016-02-29")
+
+	// Rules in a work calendar
+	c := timestamp.NewWorkCalendar(time.UTC, nil, rules)
+	friday := time.Date(2021, 12, 24, 9, 0, 0, 0, time.UTC)
+	next, err := c.AddBusinessDays(friday, 1)
+	is.NoErr(err)
+	is.Equal(next.Format("2006-01-02"), "2021-12-29")
+	set := rules.HolidaySet(2021, 2022)
+	is.Equal(set[timestamp.Date{Year: 2021, Month: time.December, Day: 28}], "Boxing Day")
+	is.Equal(set[timestamp.Date{Year: 2021, Month: time.December, Day: 26}], "Boxing Day")
+
+	for _, text := range []string{
+		"No colon 01-01",
+		"Bad: 02-30",
+		"Bad: sixth monday of may",
+		"Bad: first moonday of may",
+		"Bad: first monday of maybe",
+		"Bad: easter +x",
+		"Bad: 01-01 observed sometimes",
+		"Bad: 01-01 from",
+		"Bad: 01-01 since 2000",
+		"Bad:",
+	} {
+		_, err := timestamp.ParseHolidayRules(text)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+}
+
+func TestHolidayRulesConcurrent(t *testing.T) {
+	is := is.New(t)
+
+	rules, err := timestamp.ParseHolidayRules(holidayText)
+	is.NoErr(err)
+
+	// Years are worked out by whichever caller gets there first
+	counts := make([]int, 8)
+	var wg sync.WaitGroup
+	for n := range counts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := (timestamp.Date{Year: 2020, Month: time.January, Day: 1}); d.Year < 2030; d = d.AddDays(1) {
+				if rules.IsHoliday(d) {
+					counts[n]++
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	for _, count := range counts {
+		is.Equal(count, counts[0])
+	}
+}
+
+func TestHolidayRulesRoundTrip(t *testing.T) {
+	is := is.New(t)
+
+	rules, err := timestamp.ParseHolidayRules(holidayText)
+	is.NoErr(err)
+
+	// Text written by String reads back the same
+	var text string
+	for _, rule := range rules.Rules() {
+		text += rule.String() + "\n"
+	}
+	again, err := timestamp.ParseHolidayRules(text)
+	is.NoErr(err)
+	is.Equal(again.Rules(), rules.Rules())
+	is.Equal(rules.Rules()[6].String(), "Thanksgiving: fourth thursday of november")
+
+	data, err := json.Marshal(rules)
+	is.NoErr(err)
+	t.Log(string(data))
+	fromJSON, err := timestamp.ParseHolidayRulesJSON(data)
+	is.NoErr(err)
+	is.Equal(fromJSON.Rules(), rules.Rules())
+
+	fromJSON, err = timestamp.ParseHolidayRulesJSON([]byte(`[
+		{"name": "Canada Day", "rule": "07-01", "observed": "monday", "from": 1983},
+		{"name": "Labour Day", "rule": "first monday of september"}
+	]`))
+	is.NoErr(err)
+	is.Equal(fromJSON.Rules()[0].String(), "Canada Day: 07-01 observed monday from 1983")
+	is.Equal(fromJSON.Rules()[1].Nth, 1)
+
+	_, err = timestamp.ParseHolidayRulesJSON([]byte(`[{"name": "Bad", "rule": "13-01"}]`))
+	is.True(err != nil) // Should fail
+	t.Log(err)
+}
@@ -0,0 +1,336 @@
+package timestamp
+
+import (
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// Phrases the words a Humanizer writes. Replace them to translate output.
+type Phrases struct {
+	Now           string            // written when the difference is too small to show
+	PastPrefix    string            // written before a past difference
+	PastSuffix    string            // written after a past difference
+	FuturePrefix  string            // written before a future difference
+	FutureSuffix  string            // written after a future difference
+	Units         map[Unit][]string // forms of each unit name, chosen with Plural
+	Abbreviations map[Unit]string   // abbreviated unit names written straight after the count
+	Plural        func(n int) int   // index into Units forms for a count, English rules if nil
+	Separator     string            // between units
+	LastSeparator string            // before the last of several units
+}
+
+// EnglishPhrases the default phrases, giving output such as "3 hours ago",
+// "in 2 days" and "3h ago"
+var EnglishPhrases = Phrases{
+	Now:          "just now",
+	PastSuffix:   " ago",
+	FuturePrefix: "in ",
+	Units: map[Unit][]string{
+		Nanosecond:  {"nanosecond", "nanoseconds"},
+		Microsecond: {"microsecond", "microseconds"},
+		Millisecond: {"millisecond", "milliseconds"},
+		Second:      {"second", "seconds"},
+		Minute:      {"minute", "minutes"},
+		Hour:        {"hour", "hours"},
+		Day:         {"day", "days"},
+		Week:        {"week", "weeks"},
+		Month:       {"month", "months"},
+		Quarter:     {"quarter", "quarters"},
+		Year:        {"year", "years"},
+	},
+	Abbreviations: map[Unit]string{
+		Nanosecond: "ns", Microsecond: "µs", Millisecond: "ms", Second: "s", Minute: "m", Hour: "h",
+		Day: "d", Week: "w", Month: "mo", Quarter: "q", Year: "y",
+	},
+	Separator:     ", ",
+	LastSeparator: " and ",
+}
+
+// defaultHumanizeUnits the units a Humanizer shows if none are set
+var defaultHumanizeUnits = []Unit{Year, Month, Day, Hour, Minute, Second}
+
+// approximateDurations the length used for calendar units when a Humanizer
+// is not counting on the calendar
+var approximateDurations = [...]time.Duration{
+	Day:     24 * time.Hour,
+	Week:    7 * 24 * time.Hour,
+	Month:   30 * 24 * time.Hour,
+	Quarter: 91 * 24 * time.Hour,
+	Year:    365 * 24 * time.Hour,
+}
+
+// Humanizer writes the difference between a time and a reference time in
+// words, such as "3 hours ago", "in 2 days" or "1 day and 4 hours ago".
+//
+// The largest unit with a count of at least its threshold leads and up to
+// Granularity units are shown from there, with units whose count is zero left
+// out. The last unit shown is rounded to the nearest whole unit.
+//
+// Without Calendar days are 24 hours, weeks 7 days, months 30 days, quarters
+// 91 days and years 365 days. With Calendar, days and larger units are
+// counted on the calendar in the location of the reference time, so Feb 29
+// to Mar 31 is a month and 2 days, and a day across a daylight saving change
+// is a day even though it is 23 or 25 hours long.
+type Humanizer struct {
+	Phrases     *Phrases      // words to use, EnglishPhrases if nil
+	Units       []Unit        // units that may be shown, Year, Month, Day, Hour, Minute and Second if empty
+	Granularity int           // most units shown, 1 if 0
+	Thresholds  map[Unit]int  // smallest count of a unit for it to lead, 1 if not set
+	Calendar    bool          // count days and larger units on the calendar
+	Abbreviated bool          // write 3h rather than 3 hours
+	JustNow     time.Duration // differences smaller than this are written as Phrases.Now
+}
+
+// Humanize write the difference between t and ref in English with the
+// default Humanizer, such as "3 hours ago" or "in 2 days"
+func Humanize(t, ref time.Time) string {
+	return Humanizer{}.Format(t, ref)
+}
+
+// Format write the difference between t and ref
+func (h Humanizer) Format(t, ref time.Time) string {
+	xfmtBuf := make(xfmt.Buffer, 0, 32)
+	h.Append(&xfmtBuf, t, ref)
+
+	return string(xfmtBuf.Bytes())
+}
+
+// humanizeCounts the count of each unit, indexed by unit
+type humanizeCounts [Year + 1]int
+
+// Append write the difference between t and ref to an xfmt.Buffer
+func (h Humanizer) Append(buf *xfmt.Buffer, t, ref time.Time) *xfmt.Buffer {
+	phrases := h.Phrases
+	if phrases == nil {
+		phrases = &EnglishPhrases
+	}
+
+	earlier, later := t, ref
+	past := t.Before(ref)
+	if !past {
+		earlier, later = ref, t
+	}
+	if h.Calendar {
+		earlier, later = earlier.In(ref.Location()), later.In(ref.Location())
+	}
+
+	if later.Sub(earlier) < h.JustNow {
+		return buf.S(phrases.Now)
+	}
+
+	// Find the shown units and round to the last of them, then count again
+	// from the rounded time so rounding can carry into larger units
+	first, last, boundary, next := h.split(earlier, later)
+	if last == 0 {
+		return buf.S(phrases.Now)
+	}
+	if later.Sub(boundary)*2 >= next.Sub(boundary) {
+		later = next
+	} else {
+		later = boundary
+	}
+	counts, first, last := h.count(earlier, later)
+	if first == 0 {
+		return buf.S(phrases.Now)
+	}
+
+	shown := 0
+	for u := first; u >= last; u-- {
+		if counts[u] != 0 {
+			shown++
+		}
+	}
+
+	if past {
+		buf.S(phrases.PastPrefix)
+	} else {
+		buf.S(phrases.FuturePrefix)
+	}
+	written := 0
+	for u := first; u >= last; u-- {
+		n := counts[u]
+		if n == 0 {
+			continue
+		}
+		if written > 0 {
+			switch {
+			case h.Abbreviated:
+				buf.C(' ')
+			case written == shown-1:
+				buf.S(phrases.LastSeparator)
+			default:
+				buf.S(phrases.Separator)
+			}
+		}
+		written++
+
+		buf.D(n)
+		if h.Abbreviated {
+			buf.S(phrases.Abbreviations[u])
+			continue
+		}
+		forms := phrases.Units[u]
+		form := 0
+		if phrases.Plural != nil {
+			form = phrases.Plural(n)
+		} else if n != 1 {
+			form = 1
+		}
+		if form >= 0 && form < len(forms) {
+			buf.C(' ').S(forms[form])
+		}
+	}
+	if past {
+		buf.S(phrases.PastSuffix)
+	} else {
+		buf.S(phrases.FutureSuffix)
+	}
+
+	return buf
+}
+
+// units get the set of units shown as a mask indexed by unit
+func (h Humanizer) units() (mask uint) {
+	units := h.Units
+	if len(units) == 0 {
+		units = defaultHumanizeUnits
+	}
+	for _, u := range units {
+		if u >= Nanosecond && u <= Year {
+			mask |= 1 << uint(u)
+		}
+	}
+	return
+}
+
+// length get the length of a unit, approximate for calendar units
+func (h Humanizer) length(unit Unit) time.Duration {
+	if unit.IsCalendar() {
+		return approximateDurations[unit]
+	}
+	return unit.Duration()
+}
+
+// secondsBetween count the whole seconds from t to a later end without the
+// limit of about 292 years that time.Time.Sub has
+func secondsBetween(t, end time.Time) int64 {
+	seconds := end.Unix() - t.Unix()
+	if end.Nanosecond() < t.Nanosecond() {
+		seconds--
+	}
+	return seconds
+}
+
+// step add n of a unit, on the calendar or as an approximate duration. Units
+// of a second and longer are added as seconds so large spans don't overflow
+// a time.Duration.
+func (h Humanizer) step(t time.Time, n int, unit Unit) time.Time {
+	if unit.IsCalendar() && h.Calendar {
+		return addUnits(t, n, unit)
+	}
+	length := h.length(unit)
+	if length < time.Second {
+		return t.Add(time.Duration(n) * length)
+	}
+	return time.Unix(t.Unix()+int64(n)*int64(length/time.Second), int64(t.Nanosecond())).In(t.Location())
+}
+
+// whole count the whole units from t that fit before end
+func (h Humanizer) whole(t, end time.Time, unit Unit) int {
+	if !unit.IsCalendar() || !h.Calendar {
+		length := h.length(unit)
+		if length < time.Second {
+			return int(end.Sub(t) / length)
+		}
+		return int(secondsBetween(t, end) / int64(length/time.Second))
+	}
+
+	// Calendar units vary in length so estimate from the dates and correct
+	// for the wall clock
+	from, to := DateOf(t), DateOf(end)
+	months := (to.Year-from.Year)*12 + int(to.Month-from.Month)
+	var n int
+	switch unit {
+	case Day:
+		n = to.DaysSince(from)
+	case Week:
+		n = to.DaysSince(from) / 7
+	case Month:
+		n = months
+	case Quarter:
+		n = months / 3
+	case Year:
+		n = months / 12
+	}
+	for n > 0 && h.step(t, n, unit).After(end) {
+		n--
+	}
+	for !h.step(t, n+1, unit).After(end) {
+		n++
+	}
+	return n
+}
+
+// count count units from earlier to later, starting with the leading unit
+// and stopping after Granularity units. The first and last units counted are
+// returned, with first 0 if nothing was counted.
+func (h Humanizer) count(earlier, later time.Time) (counts humanizeCounts, first, last Unit) {
+	mask := h.units()
+	granularity := h.Granularity
+	if granularity < 1 {
+		granularity = 1
+	}
+
+	cursor := earlier
+	for u := Year; u >= Nanosecond; u-- {
+		if mask&(1<<uint(u)) == 0 {
+			continue
+		}
+		n := h.whole(cursor, later, u)
+		if first == 0 {
+			threshold := h.Thresholds[u]
+			if threshold < 1 {
+				threshold = 1
+			}
+			if n < threshold {
+				continue
+			}
+			first = u
+		}
+		counts[u] = n
+		cursor = h.step(cursor, n, u)
+		last = u
+		granularity--
+		if granularity == 0 {
+			break
+		}
+	}
+
+	return
+}
+
+// split find the last unit shown and the boundaries either side of later in
+// that unit
+func (h Humanizer) split(earlier, later time.Time) (first, last Unit, boundary, next time.Time) {
+	counts, first, last := h.count(earlier, later)
+	if first == 0 {
+		// Nothing reaches its threshold so round in the smallest unit
+		for u := Nanosecond; u <= Year; u++ {
+			if h.units()&(1<<uint(u)) != 0 {
+				return 0, u, earlier, h.step(earlier, 1, u)
+			}
+		}
+		return
+	}
+
+	boundary = earlier
+	mask := h.units()
+	for u := first; u >= last; u-- {
+		if mask&(1<<uint(u)) != 0 {
+			boundary = h.step(boundary, counts[u], u)
+		}
+	}
+
+	return first, last, boundary, h.step(boundary, 1, last)
+}
@@ -0,0 +1,137 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/imarsman/timestamp/pkg/xfmt"
+	"github.com/matryer/is"
+)
+
+func TestHumanize(t *testing.T) {
+	is := is.New(t)
+
+	ref := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		t        time.Time
+		expected string
+	}{
+		{ref, "just now"},
+		{ref.Add(-300 * time.Millisecond), "just now"},
+		{ref.Add(-time.Second), "1 second ago"},
+		{ref.Add(-3 * time.Hour), "3 hours ago"},
+		{ref.Add(-3*time.Hour - 40*time.Minute), "4 hours ago"},
+		{ref.Add(-59*time.Minute - 40*time.Second), "1 hour ago"},
+		{ref.Add(48 * time.Hour), "in 2 days"},
+		{ref.Add(45 * 24 * time.Hour), "in 2 months"},
+		{ref.Add(-400 * 24 * time.Hour), "1 year ago"},
+	}
+
+	for _, test := range tests {
+		got := timestamp.Humanize(test.t, ref)
+		t.Logf("%v got %q", test.t.Sub(ref), got)
+		is.Equal(got, test.expected) // Output should match
+	}
+}
+
+// Spans past the 292 years a time.Duration holds are still counted
+func TestHumanizeLargeSpans(t *testing.T) {
+	is := is.New(t)
+
+	ref := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	is.Equal(timestamp.Humanize(ref.AddDate(-400, 0, 0), ref), "400 years ago")
+	is.Equal(timestamp.Humanize(ref.AddDate(1000, 0, 0), ref), "in 1001 years")
+
+	calendar := timestamp.Humanizer{Calendar: true, Granularity: 2}
+	is.Equal(calendar.Format(ref.AddDate(-400, -2, 0), ref), "400 years and 2 months ago")
+	is.Equal(calendar.Format(ref.AddDate(1000, 5, 0), ref), "in 1000 years and 5 months")
+
+	hours := timestamp.Humanizer{Units: []timestamp.Unit{timestamp.Hour}}
+	is.Equal(hours.Format(ref.AddDate(-400, 0, 0), ref), "3506328 hours ago")
+}
+
+func TestHumanizerOptions(t *testing.T) {
+	is := is.New(t)
+
+	ref := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		h        timestamp.Humanizer
+		t        time.Time
+		expected string
+	}{
+		{timestamp.Humanizer{Abbreviated: true}, ref.Add(-3 * time.Hour), "3h ago"},
+		{timestamp.Humanizer{Abbreviated: true, Granularity: 2}, ref.Add(26*time.Hour + 5*time.Minute), "in 1d 2h"},
+		{timestamp.Humanizer{Granularity: 2}, ref.Add(-26*time.Hour - 5*time.Minute), "1 day and 2 hours ago"},
+		{timestamp.Humanizer{Granularity: 3}, ref.Add(-26*time.Hour - 5*time.Minute), "1 day, 2 hours and 5 minutes ago"},
+		{timestamp.Humanizer{Granularity: 2}, ref.Add(-24*time.Hour - 5*time.Minute), "1 day ago"},
+		{timestamp.Humanizer{Thresholds: map[timestamp.Unit]int{timestamp.Day: 2}}, ref.Add(-36 * time.Hour), "36 hours ago"},
+		{timestamp.Humanizer{Units: []timestamp.Unit{timestamp.Week, timestamp.Day}}, ref.Add(-15 * 24 * time.Hour), "2 weeks ago"},
+		{timestamp.Humanizer{JustNow: time.Minute}, ref.Add(-45 * time.Second), "just now"},
+		{timestamp.Humanizer{}, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), "1 month ago"},
+		{timestamp.Humanizer{Calendar: true, Granularity: 2}, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC), "1 month and 2 days ago"},
+		{timestamp.Humanizer{Calendar: true}, time.Date(2023, 3, 31, 12, 0, 0, 0, time.UTC), "1 year ago"},
+		{timestamp.Humanizer{}, time.Date(2023, 3, 31, 12, 0, 0, 0, time.UTC), "1 year ago"},
+	}
+
+	for _, test := range tests {
+		got := test.h.Format(test.t, ref)
+		t.Logf("%v got %q", test.t.Sub(ref), got)
+		is.Equal(got, test.expected) // Output should match
+	}
+}
+
+func TestHumanizerCalendarDST(t *testing.T) {
+	is := is.New(t)
+
+	newYork, err := time.LoadLocation("America/New_York")
+	is.NoErr(err)
+
+	// 23 hours of elapsed time across the spring change is a calendar day
+	ref := time.Date(2024, 3, 10, 12, 0, 0, 0, newYork)
+	before := time.Date(2024, 3, 9, 12, 0, 0, 0, newYork)
+	is.Equal(timestamp.Humanizer{Calendar: true}.Format(before, ref), "1 day ago")
+	is.Equal(timestamp.Humanizer{Units: []timestamp.Unit{timestamp.Hour}}.Format(before, ref), "23 hours ago")
+}
+
+func TestHumanizerPhrases(t *testing.T) {
+	is := is.New(t)
+
+	german := timestamp.Phrases{
+		Now:          "gerade eben",
+		PastPrefix:   "vor ",
+		FuturePrefix: "in ",
+		Units: map[timestamp.Unit][]string{
+			timestamp.Hour: {"Stunde", "Stunden"},
+			timestamp.Day:  {"Tag", "Tagen"},
+		},
+		Separator:     ", ",
+		LastSeparator: " und ",
+	}
+	h := timestamp.Humanizer{Phrases: &german, Units: []timestamp.Unit{timestamp.Day, timestamp.Hour}, Granularity: 2}
+
+	ref := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	is.Equal(h.Format(ref.Add(-3*time.Hour), ref), "vor 3 Stunden")
+	is.Equal(h.Format(ref.Add(25*time.Hour), ref), "in 1 Tag und 1 Stunde")
+	is.Equal(h.Format(ref, ref), "gerade eben")
+
+	buf := new(xfmt.Buffer)
+	h.Append(buf.S("["), ref.Add(-48*time.Hour), ref).C(']')
+	is.Equal(string(buf.Bytes()), "[vor 2 Tagen]")
+}
+
+func BenchmarkHumanizerAppend(b *testing.B) {
+	ref := time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC)
+	ts := ref.Add(-26*time.Hour - 5*time.Minute)
+	h := timestamp.Humanizer{Granularity: 2}
+	buf := make(xfmt.Buffer, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		h.Append(&buf, ts, ref)
+	}
+}
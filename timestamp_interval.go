@@ -0,0 +1,206 @@
+package timestamp
+
+import (
+	"slices"
+	"time"
+)
+
+// Interval a half-open span of time [Start, End) that includes Start and
+// excludes End. Comparisons are to the nanosecond. An interval whose End is
+// not after its Start is empty.
+type Interval struct {
+	Start time.Time
+	End   time.Time
+}
+
+// IsEmpty does the interval contain no instants
+func (i Interval) IsEmpty() bool {
+	return !i.End.After(i.Start)
+}
+
+// Duration get the length of the interval, 0 if it is empty
+func (i Interval) Duration() time.Duration {
+	if i.IsEmpty() {
+		return 0
+	}
+	return i.End.Sub(i.Start)
+}
+
+// Contains is t at or after Start and before End
+func (i Interval) Contains(t time.Time) bool {
+	return !t.Before(i.Start) && t.Before(i.End)
+}
+
+// Overlaps do the intervals share an instant. Intervals that only touch, where
+// one ends as the other starts, do not overlap.
+func (i Interval) Overlaps(other Interval) bool {
+	return i.Start.Before(other.End) && other.Start.Before(i.End) && !i.IsEmpty() && !other.IsEmpty()
+}
+
+// Intersect get the instants in both intervals, which may be empty
+func (i Interval) Intersect(other Interval) Interval {
+	return Interval{Start: laterOf(i.Start, other.Start), End: earlierOf(i.End, other.End)}
+}
+
+// String get the interval in the ISO-8601 start/end form
+func (i Interval) String() string {
+	return i.Start.Format(time.RFC3339Nano) + "/" + i.End.Format(time.RFC3339Nano)
+}
+
+// earlierOf get the earlier of two times
+func earlierOf(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// laterOf get the later of two times
+func laterOf(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// IntervalSet a set of instants made of intervals. The intervals are kept
+// sorted with none empty and none overlapping or touching, so each set has one
+// form. The zero value is the empty set. Operations return new sets and never
+// change their inputs.
+type IntervalSet struct {
+	intervals []Interval
+}
+
+// NewIntervalSet make a set covering the instants of the intervals. Empty
+// intervals are dropped and overlapping or touching intervals are merged.
+func NewIntervalSet(intervals ...Interval) IntervalSet {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, i := range intervals {
+		if !i.IsEmpty() {
+			sorted = append(sorted, i)
+		}
+	}
+	slices.SortFunc(sorted, func(a, b Interval) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	merged := sorted[:0]
+	for _, i := range sorted {
+		if n := len(merged); n > 0 && !i.Start.After(merged[n-1].End) {
+			merged[n-1].End = laterOf(merged[n-1].End, i.End)
+			continue
+		}
+		merged = append(merged, i)
+	}
+
+	return IntervalSet{intervals: merged}
+}
+
+// Intervals get a copy of the intervals in the set in order
+func (s IntervalSet) Intervals() []Interval {
+	return slices.Clone(s.intervals)
+}
+
+// IsEmpty does the set contain no instants
+func (s IntervalSet) IsEmpty() bool {
+	return len(s.intervals) == 0
+}
+
+// Duration get the total time covered by the set
+func (s IntervalSet) Duration() (total time.Duration) {
+	for _, i := range s.intervals {
+		total += i.Duration()
+	}
+	return
+}
+
+// Bounds get the interval from the start of the first member to the end of
+// the last, which is empty for an empty set
+func (s IntervalSet) Bounds() Interval {
+	if s.IsEmpty() {
+		return Interval{}
+	}
+	return Interval{Start: s.intervals[0].Start, End: s.intervals[len(s.intervals)-1].End}
+}
+
+// Contains is t in one of the intervals of the set
+func (s IntervalSet) Contains(t time.Time) bool {
+	// Find the first interval ending after t
+	n, _ := slices.BinarySearchFunc(s.intervals, t, func(i Interval, t time.Time) int {
+		if i.End.After(t) {
+			return 1
+		}
+		return -1
+	})
+	return n < len(s.intervals) && s.intervals[n].Contains(t)
+}
+
+// Union get the instants in either set
+func (s IntervalSet) Union(other IntervalSet) IntervalSet {
+	return NewIntervalSet(append(slices.Clone(s.intervals), other.intervals...)...)
+}
+
+// Intersect get the instants in both sets
+func (s IntervalSet) Intersect(other IntervalSet) IntervalSet {
+	var result []Interval
+	a, b := s.intervals, other.intervals
+	for len(a) > 0 && len(b) > 0 {
+		if i := a[0].Intersect(b[0]); !i.IsEmpty() {
+			result = append(result, i)
+		}
+		// Drop whichever interval ends first since it can't meet anything
+		// later in the other set
+		if a[0].End.Before(b[0].End) {
+			a = a[1:]
+		} else {
+			b = b[1:]
+		}
+	}
+
+	return IntervalSet{intervals: result}
+}
+
+// Subtract get the instants in s that are not in other
+func (s IntervalSet) Subtract(other IntervalSet) IntervalSet {
+	var result []Interval
+	b := other.intervals
+	for _, i := range s.intervals {
+		// Skip intervals of other that end before this one starts
+		for len(b) > 0 && !b[0].End.After(i.Start) {
+			b = b[1:]
+		}
+		rest := i
+		for _, cut := range b {
+			if !cut.Start.Before(rest.End) {
+				break
+			}
+			if cut.Start.After(rest.Start) {
+				result = append(result, Interval{Start: rest.Start, End: cut.Start})
+			}
+			rest.Start = laterOf(rest.Start, cut.End)
+		}
+		if !rest.IsEmpty() {
+			result = append(result, rest)
+		}
+	}
+
+	return IntervalSet{intervals: result}
+}
+
+// Complement get the instants within bound that are not in the set
+func (s IntervalSet) Complement(bound Interval) IntervalSet {
+	return NewIntervalSet(bound).Subtract(s)
+}
+
+// Gaps get the spaces between the intervals of the set that are at least
+// minimum long. Use Complement to include the time before the first and after
+// the last interval.
+func (s IntervalSet) Gaps(minimum time.Duration) (gaps []Interval) {
+	for n := 1; n < len(s.intervals); n++ {
+		gap := Interval{Start: s.intervals[n-1].End, End: s.intervals[n].Start}
+		if gap.Duration() >= minimum {
+			gaps = append(gaps, gap)
+		}
+	}
+	return
+}
@@ -0,0 +1,77 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+// hours make an interval between two hours of a day
+func hours(start, end float64) timestamp.Interval {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return timestamp.Interval{
+		Start: base.Add(time.Duration(start * float64(time.Hour))),
+		End:   base.Add(time.Duration(end * float64(time.Hour))),
+	}
+}
+
+func TestInterval(t *testing.T) {
+	is := is.New(t)
+
+	i := hours(9, 17)
+	is.Equal(i.Duration(), 8*time.Hour)
+	is.True(i.Contains(i.Start)) // Start should be included
+	is.True(!i.Contains(i.End))  // End should be excluded
+	is.True(i.Contains(i.End.Add(-time.Nanosecond)))
+	is.True(!i.Overlaps(hours(17, 18))) // Touching intervals should not overlap
+	is.True(i.Overlaps(hours(16, 18)))
+	is.Equal(i.Intersect(hours(16, 18)), hours(16, 17))
+	is.True(i.Intersect(hours(18, 19)).IsEmpty())
+	is.True(hours(3, 2).IsEmpty())
+	is.Equal(hours(3, 2).Duration(), time.Duration(0))
+
+	// Times less than a second apart are ordered to the nanosecond
+	start := time.Date(2024, 1, 1, 0, 0, 0, 100, time.UTC)
+	is.True(!timestamp.Interval{Start: start, End: start.Add(time.Millisecond)}.IsEmpty())
+	is.True(!timestamp.StartTimeIsBeforeEndTime(start, start.Add(time.Millisecond)))
+}
+
+func TestIntervalSet(t *testing.T) {
+	is := is.New(t)
+
+	s := timestamp.NewIntervalSet(hours(13, 15), hours(9, 10), hours(10, 12), hours(14, 16), hours(20, 20))
+	is.Equal(s.Intervals(), []timestamp.Interval{hours(9, 12), hours(13, 16)}) // Should normalize
+	is.Equal(s.Duration(), 6*time.Hour)
+	is.Equal(s.Bounds(), hours(9, 16))
+
+	is.True(s.Contains(hours(9, 10).Start))
+	is.True(s.Contains(hours(11.5, 12).Start))
+	is.True(!s.Contains(hours(12, 13).Start)) // End of a member should be excluded
+	is.True(s.Contains(hours(15.9, 16).Start))
+	is.True(!s.Contains(hours(16, 17).Start))
+	is.True(!s.Contains(hours(8, 9).Start))
+
+	other := timestamp.NewIntervalSet(hours(11, 14), hours(15, 18))
+	is.Equal(s.Union(other).Intervals(), []timestamp.Interval{hours(9, 18)})
+	is.Equal(s.Intersect(other).Intervals(), []timestamp.Interval{hours(11, 12), hours(13, 14), hours(15, 16)})
+	is.Equal(s.Subtract(other).Intervals(), []timestamp.Interval{hours(9, 11), hours(14, 15)})
+	is.Equal(other.Subtract(s).Intervals(), []timestamp.Interval{hours(12, 13), hours(16, 18)})
+
+	// A subtraction that splits one interval several times
+	cuts := timestamp.NewIntervalSet(hours(9.5, 10), hours(10.5, 11), hours(11.5, 13.5))
+	is.Equal(s.Subtract(cuts).Intervals(), []timestamp.Interval{hours(9, 9.5), hours(10, 10.5), hours(11, 11.5), hours(13.5, 16)})
+
+	is.Equal(s.Complement(hours(8, 17)).Intervals(), []timestamp.Interval{hours(8, 9), hours(12, 13), hours(16, 17)})
+	is.Equal(s.Gaps(0), []timestamp.Interval{hours(12, 13)})
+	is.Equal(len(s.Gaps(2*time.Hour)), 0)
+
+	var empty timestamp.IntervalSet
+	is.True(empty.IsEmpty())
+	is.True(empty.Intersect(s).IsEmpty())
+	is.Equal(empty.Union(s).Intervals(), s.Intervals())
+	is.Equal(s.Subtract(empty).Intervals(), s.Intervals())
+	is.Equal(empty.Complement(hours(1, 2)).Intervals(), []timestamp.Interval{hours(1, 2)})
+	is.True(empty.Bounds().IsEmpty())
+}
@@ -0,0 +1,170 @@
+package timestamp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// ISOOffset how an ISOFormat writes the UTC offset
+type ISOOffset int
+
+// Offset styles for ISOFormat
+const (
+	ISOOffsetDefault ISOOffset = iota // +07:00 in extended form and +0700 in basic form
+	ISOOffsetColon                    // always +07:00
+	ISOOffsetNoColon                  // always +0700
+	ISOOffsetHours                    // +07 for whole hours, otherwise as for the default
+	ISOOffsetNone                     // no offset, for local times
+)
+
+// ISOFormat options for formatting ISO-8601 timestamps. The zero value
+// formats as 2006-01-02T15:04:05+07:00.
+//
+// Precision is the number of fraction of a second digits from 0 to 9. With
+// TrimFraction trailing zeros are removed from the fraction and the decimal
+// point is left out for a whole second; a Precision of 0 with TrimFraction
+// means up to 9 digits.
+//
+// Basic gives the form without separators, as in 20060102T150405+0700.
+// UTCDesignator writes Z rather than +00:00 for a zero offset. Separator is
+// written between the date and time and is T if empty; a space is common in
+// SQL and logs.
+type ISOFormat struct {
+	Precision     int       // fraction digits, 0 to 9
+	TrimFraction  bool      // remove trailing zeros from the fraction
+	Basic         bool      // basic form with no - or : separators
+	UTCDesignator bool      // Z for a zero offset
+	Separator     string    // text between the date and time, T if empty
+	Offset        ISOOffset // offset style
+}
+
+// Presets matching the ISO8601, ISO8601Msec, ISO8601Compact and
+// ISO8601CompactMsec functions
+var (
+	ISO8601Format            = ISOFormat{}
+	ISO8601MsecFormat        = ISOFormat{Precision: 3}
+	ISO8601CompactFormat     = ISOFormat{Basic: true}
+	ISO8601CompactMsecFormat = ISOFormat{Precision: 3, Basic: true}
+)
+
+// ISOFormatter formats timestamps with a compiled ISOFormat. It is safe for
+// concurrent use.
+type ISOFormatter struct {
+	layout []layoutField
+}
+
+// NewISOFormatter compile an ISOFormat. An error is returned for a precision
+// outside of 0 to 9 or an unknown offset style.
+func NewISOFormatter(f ISOFormat) (*ISOFormatter, error) {
+	if f.Precision < 0 || f.Precision > 9 {
+		xfmtBuf := new(xfmt.Buffer)
+		xfmtBuf.S("timestamp.NewISOFormatter: precision must be 0 to 9, got ").D(f.Precision)
+		return nil, errors.New(BytesToString(xfmtBuf.Bytes()...))
+	}
+	if f.Offset < ISOOffsetDefault || f.Offset > ISOOffsetNone {
+		return nil, errors.New("timestamp.NewISOFormatter: unknown offset style")
+	}
+
+	dateSep, timeSep := "-", ":"
+	if f.Basic {
+		dateSep, timeSep = "", ""
+	}
+	separator := f.Separator
+	if separator == "" {
+		separator = "T"
+	}
+
+	var layout []layoutField
+	add := func(kind fieldKind, width int) {
+		layout = append(layout, layoutField{kind: kind, width: width, pad: '0'})
+	}
+	add(fieldYear, 4)
+	layout = appendLiteral(layout, dateSep)
+	add(fieldMonth, 2)
+	layout = appendLiteral(layout, dateSep)
+	add(fieldDay, 2)
+	layout = appendLiteral(layout, separator)
+	add(fieldHour, 2)
+	layout = appendLiteral(layout, timeSep)
+	add(fieldMinute, 2)
+	layout = appendLiteral(layout, timeSep)
+	add(fieldSecond, 2)
+
+	switch {
+	case f.TrimFraction:
+		digits := f.Precision
+		if digits == 0 {
+			digits = 9
+		}
+		layout = append(layout, layoutField{kind: fieldFraction, width: digits, flags: fractionTrim, text: "."})
+	case f.Precision > 0:
+		layout = appendLiteral(layout, ".")
+		layout = append(layout, layoutField{kind: fieldFraction, width: f.Precision})
+	}
+
+	if f.Offset != ISOOffsetNone {
+		flags := 0
+		switch {
+		case f.Offset == ISOOffsetColon:
+			flags = offsetColon
+		case f.Offset == ISOOffsetNoColon:
+		case !f.Basic:
+			flags = offsetColon
+		}
+		if f.Offset == ISOOffsetHours {
+			flags |= offsetHours
+		}
+		if f.UTCDesignator {
+			flags |= offsetZ
+		}
+		layout = append(layout, layoutField{kind: fieldOffset, flags: flags})
+	}
+
+	// Drop empty literals left by the basic form
+	compact := layout[:0]
+	for _, field := range layout {
+		if field.kind != fieldLiteral || field.text != "" {
+			compact = append(compact, field)
+		}
+	}
+
+	return &ISOFormatter{layout: compact}, nil
+}
+
+// mustISOFormatter compile an ISOFormat known to be valid
+func mustISOFormatter(f ISOFormat) *ISOFormatter {
+	formatter, err := NewISOFormatter(f)
+	if err != nil {
+		panic(err)
+	}
+	return formatter
+}
+
+// FormatISO format a time with ISO-8601 options. Use NewISOFormatter to
+// compile the options once when formatting many times.
+func FormatISO(t time.Time, f ISOFormat) (string, error) {
+	formatter, err := NewISOFormatter(f)
+	if err != nil {
+		return "", err
+	}
+	return formatter.Format(t), nil
+}
+
+// Format format a time. The result is in the location of t.
+func (f *ISOFormatter) Format(t time.Time) string {
+	return formatLayout(t, f.layout)
+}
+
+// Append append a formatted time to b and return the extended slice. Nothing
+// is allocated if b has room.
+func (f *ISOFormatter) Append(b []byte, t time.Time) []byte {
+	return appendLayout(b, t, f.layout)
+}
+
+// Buffer write a formatted time to an xfmt.Buffer
+func (f *ISOFormatter) Buffer(buf *xfmt.Buffer, t time.Time) *xfmt.Buffer {
+	*buf = appendLayout(*buf, t, f.layout)
+	return buf
+}
@@ -0,0 +1,96 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestFormatISO(t *testing.T) {
+	is := is.New(t)
+
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123450000, ist)
+	utc := time.Date(2021, 1, 3, 17, 4, 5, 0, time.UTC)
+	est := time.Date(2021, 1, 3, 17, 4, 5, 500000000, time.FixedZone("EST", -5*60*60))
+
+	tests := []struct {
+		ts       time.Time
+		format   timestamp.ISOFormat
+		expected string
+	}{
+		{ts, timestamp.ISOFormat{}, "2021-01-03T17:04:05+05:30"},
+		{ts, timestamp.ISOFormat{Precision: 6}, "2021-01-03T17:04:05.123450+05:30"},
+		{ts, timestamp.ISOFormat{Precision: 9}, "2021-01-03T17:04:05.123450000+05:30"},
+		{ts, timestamp.ISOFormat{TrimFraction: true}, "2021-01-03T17:04:05.12345+05:30"},
+		{ts, timestamp.ISOFormat{Precision: 3, TrimFraction: true}, "2021-01-03T17:04:05.123+05:30"},
+		{utc, timestamp.ISOFormat{TrimFraction: true}, "2021-01-03T17:04:05+00:00"},
+		{utc, timestamp.ISOFormat{UTCDesignator: true}, "2021-01-03T17:04:05Z"},
+		{ts, timestamp.ISOFormat{UTCDesignator: true}, "2021-01-03T17:04:05+05:30"},
+		{ts, timestamp.ISOFormat{Separator: " "}, "2021-01-03 17:04:05+05:30"},
+		{ts, timestamp.ISOFormat{Offset: timestamp.ISOOffsetNoColon}, "2021-01-03T17:04:05+0530"},
+		{ts, timestamp.ISOFormat{Basic: true, Offset: timestamp.ISOOffsetColon}, "20210103T170405+05:30"},
+		{ts, timestamp.ISOFormat{Basic: true, Precision: 3}, "20210103T170405.123+0530"},
+		{est, timestamp.ISOFormat{Offset: timestamp.ISOOffsetHours}, "2021-01-03T17:04:05-05"},
+		{ts, timestamp.ISOFormat{Offset: timestamp.ISOOffsetHours}, "2021-01-03T17:04:05+05:30"},
+		{est, timestamp.ISOFormat{Offset: timestamp.ISOOffsetNone, Separator: " ", TrimFraction: true}, "2021-01-03 17:04:05.5"},
+		{utc, timestamp.ISOFormat{Basic: true, UTCDesignator: true}, "20210103T170405Z"},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.FormatISO(test.ts, test.format)
+		is.NoErr(err) // Format should be valid
+		t.Logf("%+v got %s", test.format, got)
+		is.Equal(got, test.expected) // Output should match
+	}
+}
+
+func TestISOFormatPresets(t *testing.T) {
+	is := is.New(t)
+
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.UTC)
+
+	presets := []struct {
+		format   timestamp.ISOFormat
+		function func(time.Time) string
+	}{
+		{timestamp.ISO8601Format, timestamp.ISO8601},
+		{timestamp.ISO8601MsecFormat, timestamp.ISO8601Msec},
+		{timestamp.ISO8601CompactFormat, timestamp.ISO8601Compact},
+		{timestamp.ISO8601CompactMsecFormat, timestamp.ISO8601CompactMsec},
+	}
+
+	for _, preset := range presets {
+		got, err := timestamp.FormatISO(ts, preset.format)
+		is.NoErr(err)                      // Preset should be valid
+		is.Equal(got, preset.function(ts)) // Preset should match its function
+	}
+}
+
+func TestISOFormatErrors(t *testing.T) {
+	is := is.New(t)
+
+	_, err := timestamp.NewISOFormatter(timestamp.ISOFormat{Precision: 10})
+	is.True(err != nil) // Precision over 9 should fail
+	_, err = timestamp.NewISOFormatter(timestamp.ISOFormat{Precision: -1})
+	is.True(err != nil) // Negative precision should fail
+	_, err = timestamp.NewISOFormatter(timestamp.ISOFormat{Offset: timestamp.ISOOffset(99)})
+	is.True(err != nil) // Unknown offset style should fail
+}
+
+func BenchmarkISOFormatterAppend(b *testing.B) {
+	is := is.New(b)
+
+	formatter, err := timestamp.NewISOFormatter(timestamp.ISOFormat{Precision: 6, UTCDesignator: true})
+	is.NoErr(err)
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, time.UTC)
+	buf := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = formatter.Append(buf[:0], ts)
+	}
+}
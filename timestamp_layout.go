@@ -0,0 +1,787 @@
+package timestamp
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// The layout engine. Pattern languages such as strftime are compiled to a
+// slice of layoutField values, which can then be used both to format a time
+// and to parse a string. Formatting appends to a byte slice and does not
+// allocate.
+
+// fieldKind a component of a compiled layout
+type fieldKind uint8
+
+const (
+	fieldLiteral     fieldKind = iota // literal text
+	fieldYear                         // year
+	fieldYear2                        // two digit year
+	fieldCentury                      // year divided by 100
+	fieldISOYear                      // ISO-8601 week based year
+	fieldISOYear2                     // two digit ISO-8601 week based year
+	fieldMonth                        // month number 1-12
+	fieldMonthAbbr                    // Jan
+	fieldMonthName                    // January
+	fieldDay                          // day of month 1-31
+	fieldYearDay                      // day of year 1-366
+	fieldWeekdayAbbr                  // Mon
+	fieldWeekdayName                  // Monday
+	fieldWeekdayISO                   // 1 (Monday) to 7 (Sunday)
+	fieldWeekday                      // 0 (Sunday) to 6 (Saturday)
+	fieldISOWeek                      // ISO-8601 week of year 1-53
+	fieldWeekSunday                   // week of year 0-53 with weeks starting on Sunday
+	fieldWeekMonday                   // week of year 0-53 with weeks starting on Monday
+	fieldHour                         // hour 0-23
+	fieldHour12                       // hour 1-12
+	fieldMinute                       // minute 0-59
+	fieldSecond                       // second 0-59
+	fieldFraction                     // fraction of a second
+	fieldAMPM                         // AM or PM
+	fieldAMPMLower                    // am or pm
+	fieldOffset                       // numeric UTC offset
+	fieldZoneName                     // zone abbreviation such as EST
+	fieldUnix                         // seconds since the Unix epoch
+)
+
+// Offset style flags for fieldOffset
+const (
+	offsetColon         = 1 << iota // +07:00 rather than +0700
+	offsetHours                     // +07 with minutes only written if not zero
+	offsetZ                         // Z for a zero offset
+	offsetSeconds                   // seconds written if not zero
+	offsetNoMinutes                 // +07 with minutes never written
+	offsetSecondsAlways             // seconds always written
+)
+
+// Fraction flags for fieldFraction
+const (
+	fractionTrim    = 1 << iota // trailing zeros removed and the separator omitted if zero
+	fractionLenient             // any number of digits from 1 to 9 accepted when parsing
+)
+
+// layoutField one component of a compiled layout
+type layoutField struct {
+	kind  fieldKind
+	width int    // digits for numeric fields, 0 for no padding
+	pad   byte   // padding character for numeric fields
+	flags int    // style flags for offsets and fractions
+	text  string // literal text, or the separator for a trimmed fraction
+}
+
+// maxDigits the most digits a numeric field can have when parsing
+func (f layoutField) maxDigits() int {
+	switch f.kind {
+	case fieldYear, fieldISOYear:
+		if f.width > 4 {
+			return f.width
+		}
+		return 4
+	case fieldYearDay:
+		return 3
+	case fieldWeekdayISO, fieldWeekday:
+		if f.width > 1 {
+			return f.width
+		}
+		return 1
+	case fieldFraction:
+		if f.width > 0 && f.flags&(fractionTrim|fractionLenient) == 0 {
+			return f.width
+		}
+		return 9
+	case fieldUnix:
+		return 19
+	}
+	return 2
+}
+
+var longMonthNames = []string{
+	"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December",
+}
+
+var longDayNames = []string{
+	"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+}
+
+// appendInt append an integer padded to width with pad. A width of 0 means
+// no padding. As with Go's time package the sign of a negative value is not
+// counted in the width.
+func appendInt(b []byte, v int, width int, pad byte) []byte {
+	if v < 0 {
+		b = append(b, '-')
+		v = -v
+	}
+
+	// Write digits into a small array from the end
+	var digits [20]byte
+	i := len(digits)
+	for v >= 10 {
+		i--
+		digits[i] = byte('0' + v%10)
+		v /= 10
+	}
+	i--
+	digits[i] = byte('0' + v)
+
+	for n := len(digits) - i; n < width; n++ {
+		b = append(b, pad)
+	}
+
+	return append(b, digits[i:]...)
+}
+
+// appendFraction append the fraction of a second with a number of digits. With
+// trim trailing zeros are removed and nothing, including the separator, is
+// written for a zero fraction.
+func appendFraction(b []byte, nsec int, digits int, trim bool, separator string) []byte {
+	if digits <= 0 || digits > 9 {
+		digits = 9
+	}
+	v := nsec / intPow(10, 9-digits)
+	if trim {
+		for digits > 0 && v%10 == 0 {
+			v /= 10
+			digits--
+		}
+		if digits == 0 {
+			return b
+		}
+		b = append(b, separator...)
+	}
+
+	return appendInt(b, v, digits, '0')
+}
+
+// appendLayoutOffset append a UTC offset in seconds using offset style flags
+func appendLayoutOffset(b []byte, offset int, flags int) []byte {
+	if offset == 0 && flags&offsetZ != 0 {
+		return append(b, 'Z')
+	}
+	sign := byte('+')
+	if offset < 0 {
+		sign = '-'
+		offset = -offset
+	}
+	hours, minutes, seconds := offset/3600, offset/60%60, offset%60
+
+	b = append(b, sign)
+	b = appendTwoDigits(b, hours, false)
+	if flags&offsetNoMinutes != 0 {
+		return b
+	}
+	if flags&offsetHours != 0 && minutes == 0 && seconds == 0 {
+		return b
+	}
+	if flags&offsetColon != 0 {
+		b = append(b, ':')
+	}
+	b = appendTwoDigits(b, minutes, false)
+	if flags&offsetSecondsAlways != 0 || flags&offsetSeconds != 0 && seconds != 0 {
+		if flags&offsetColon != 0 {
+			b = append(b, ':')
+		}
+		b = appendTwoDigits(b, seconds, false)
+	}
+
+	return b
+}
+
+// weekOfYear get the week of the year for a day of the year (0 based) and
+// weekday, with the first week starting on the first firstDay of the year.
+// Days before it are in week 0.
+func weekOfYear(yday int, weekday time.Weekday, firstDay time.Weekday) int {
+	offset := (int(weekday) - int(firstDay) + 7) % 7
+	return (yday + 7 - offset) / 7
+}
+
+// appendLayout append t formatted with a compiled layout
+func appendLayout(b []byte, t time.Time, layout []layoutField) []byte {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	for i := 0; i < len(layout); i++ {
+		f := &layout[i]
+		switch f.kind {
+		case fieldLiteral:
+			b = append(b, f.text...)
+		case fieldYear:
+			b = appendInt(b, year, f.width, f.pad)
+		case fieldYear2:
+			b = appendInt(b, (year%100+100)%100, 2, '0')
+		case fieldCentury:
+			b = appendInt(b, year/100, f.width, f.pad)
+		case fieldISOYear:
+			isoYear, _ := t.ISOWeek()
+			b = appendInt(b, isoYear, f.width, f.pad)
+		case fieldISOYear2:
+			isoYear, _ := t.ISOWeek()
+			b = appendInt(b, (isoYear%100+100)%100, 2, '0')
+		case fieldMonth:
+			b = appendInt(b, int(month), f.width, f.pad)
+		case fieldMonthAbbr:
+			b = append(b, longMonthNames[month-1][:3]...)
+		case fieldMonthName:
+			b = append(b, longMonthNames[month-1]...)
+		case fieldDay:
+			b = appendInt(b, day, f.width, f.pad)
+		case fieldYearDay:
+			b = appendInt(b, t.YearDay(), f.width, f.pad)
+		case fieldWeekdayAbbr:
+			b = append(b, longDayNames[t.Weekday()][:3]...)
+		case fieldWeekdayName:
+			b = append(b, longDayNames[t.Weekday()]...)
+		case fieldWeekdayISO:
+			b = appendInt(b, (int(t.Weekday())+6)%7+1, f.width, f.pad)
+		case fieldWeekday:
+			b = appendInt(b, int(t.Weekday()), f.width, f.pad)
+		case fieldISOWeek:
+			_, week := t.ISOWeek()
+			b = appendInt(b, week, f.width, f.pad)
+		case fieldWeekSunday:
+			b = appendInt(b, weekOfYear(t.YearDay()-1, t.Weekday(), time.Sunday), f.width, f.pad)
+		case fieldWeekMonday:
+			b = appendInt(b, weekOfYear(t.YearDay()-1, t.Weekday(), time.Monday), f.width, f.pad)
+		case fieldHour:
+			b = appendInt(b, hour, f.width, f.pad)
+		case fieldHour12:
+			h := hour % 12
+			if h == 0 {
+				h = 12
+			}
+			b = appendInt(b, h, f.width, f.pad)
+		case fieldMinute:
+			b = appendInt(b, min, f.width, f.pad)
+		case fieldSecond:
+			b = appendInt(b, sec, f.width, f.pad)
+		case fieldFraction:
+			b = appendFraction(b, t.Nanosecond(), f.width, f.flags&fractionTrim != 0, f.text)
+		case fieldAMPM:
+			if hour < 12 {
+				b = append(b, "AM"...)
+			} else {
+				b = append(b, "PM"...)
+			}
+		case fieldAMPMLower:
+			if hour < 12 {
+				b = append(b, "am"...)
+			} else {
+				b = append(b, "pm"...)
+			}
+		case fieldOffset:
+			_, offset := t.Zone()
+			b = appendLayoutOffset(b, offset, f.flags)
+		case fieldZoneName:
+			name, _ := t.Zone()
+			b = append(b, name...)
+		case fieldUnix:
+			b = appendInt(b, int(t.Unix()), f.width, f.pad)
+		}
+	}
+
+	return b
+}
+
+// formatLayout format t with a compiled layout using an xfmt buffer. The
+// buffer starts with enough capacity for common layouts so the only
+// allocation is for the returned string.
+func formatLayout(t time.Time, layout []layoutField) string {
+	xfmtBuf := make(xfmt.Buffer, 0, 64)
+	xfmtBuf = appendLayout(xfmtBuf, t, layout)
+
+	return string(xfmtBuf.Bytes())
+}
+
+// layoutError make a parse error pointing at a position in the input
+func layoutError(caller string, reason string, value string, pos int) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.").S(caller).S(": ").S(reason)
+	if pos >= 0 && pos <= len(value) {
+		xfmtBuf.S(" at '").S(value[pos:]).S("'").C('@').D(pos)
+	}
+	xfmtBuf.S(" in input ").S(value)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// layoutValues the values found while parsing with a layout. Fields not in the
+// layout keep their unset values.
+type layoutValues struct {
+	year, month, day, yday int
+	century                int
+	year2                  int
+	isoYear, isoYear2      int
+	isoWeek                int
+	weekSunday, weekMonday int
+	weekday                int // 0 (Sunday) to 6 (Saturday)
+	hour, hour12, min, sec int
+	nsec                   int
+	pm                     int // 0 unset, 1 AM, 2 PM
+	offset                 int
+	hasOffset              bool
+	zoneName               string
+	unix                   int64
+	hasUnix                bool
+}
+
+// matchName match one of a list of names, or their three letter
+// abbreviations if abbr is set, without regard to case. The index of the name
+// and the length matched are returned.
+func matchName(value string, names []string, abbr bool) (index int, length int) {
+	for i, name := range names {
+		if abbr {
+			name = name[:3]
+		}
+		if len(value) >= len(name) && strings.EqualFold(value[:len(name)], name) {
+			return i, len(name)
+		}
+	}
+	return -1, 0
+}
+
+// parseOffset parse a UTC offset such as Z, +07, +0700, +07:00 or +07:00:00
+// and return the offset in seconds and the length of input used
+func parseOffset(value string) (offset int, length int, ok bool) {
+	if len(value) == 0 {
+		return
+	}
+	if value[0] == 'Z' || value[0] == 'z' {
+		return 0, 1, true
+	}
+	if value[0] != '+' && value[0] != '-' {
+		return
+	}
+	sign := 1
+	if value[0] == '-' {
+		sign = -1
+	}
+	i := 1
+	var parts [3]int
+	n := 0
+	for n < 3 {
+		if n > 0 && i < len(value) && value[i] == ':' {
+			i++
+		}
+		if i+2 > len(value) {
+			break
+		}
+		v, err := atoi2(value[i : i+2])
+		if err != nil {
+			break
+		}
+		parts[n] = v
+		n++
+		i += 2
+	}
+	if n == 0 {
+		return
+	}
+	// Don't consume a trailing colon that wasn't followed by digits
+	if value[i-1] == ':' {
+		i--
+	}
+	if parts[0] > 23 || parts[1] > 59 || parts[2] > 59 {
+		return
+	}
+
+	return sign * (parts[0]*3600 + parts[1]*60 + parts[2]), i, true
+}
+
+// parseLayout parse value with a compiled layout. Missing date parts default
+// to January 1 of year 0 unless a day of year or ISO week is given. If there
+// is no offset or zone in the input location is used.
+func parseLayout(caller string, value string, layout []layoutField, location *time.Location) (t time.Time, err error) {
+	v := layoutValues{
+		year: -1, month: -1, day: -1, yday: -1, century: -1, year2: -1,
+		isoYear: -1, isoYear2: -1, isoWeek: -1, weekSunday: -1, weekMonday: -1, weekday: -1,
+		hour: -1, hour12: -1,
+	}
+
+	pos := 0
+	for i := 0; i < len(layout); i++ {
+		f := &layout[i]
+
+		switch f.kind {
+		case fieldLiteral:
+			for j := 0; j < len(f.text); {
+				r, size := utf8.DecodeRuneInString(f.text[j:])
+				j += size
+				if unicode.IsSpace(r) {
+					// Whitespace in a layout matches one or more whitespace
+					// characters
+					start := pos
+					for pos < len(value) {
+						vr, vsize := utf8.DecodeRuneInString(value[pos:])
+						if !unicode.IsSpace(vr) {
+							break
+						}
+						pos += vsize
+					}
+					if pos == start {
+						return t, layoutError(caller, "expected whitespace", value, pos)
+					}
+					// Collapse whitespace in the layout as well
+					for j < len(f.text) {
+						lr, lsize := utf8.DecodeRuneInString(f.text[j:])
+						if !unicode.IsSpace(lr) {
+							break
+						}
+						j += lsize
+					}
+					continue
+				}
+				if !strings.HasPrefix(value[pos:], string(r)) {
+					return t, layoutError(caller, "expected '"+string(r)+"'", value, pos)
+				}
+				pos += size
+			}
+			continue
+
+		case fieldMonthAbbr, fieldMonthName:
+			index, length := matchName(value[pos:], longMonthNames, f.kind == fieldMonthAbbr)
+			if index < 0 {
+				return t, layoutError(caller, "expected month name", value, pos)
+			}
+			v.month = index + 1
+			pos += length
+			continue
+
+		case fieldWeekdayAbbr, fieldWeekdayName:
+			index, length := matchName(value[pos:], longDayNames, f.kind == fieldWeekdayAbbr)
+			if index < 0 {
+				return t, layoutError(caller, "expected weekday name", value, pos)
+			}
+			v.weekday = index
+			pos += length
+			continue
+
+		case fieldAMPM, fieldAMPMLower:
+			if len(value)-pos < 2 {
+				return t, layoutError(caller, "expected AM or PM", value, pos)
+			}
+			switch strings.ToUpper(value[pos : pos+2]) {
+			case "AM":
+				v.pm = 1
+			case "PM":
+				v.pm = 2
+			default:
+				return t, layoutError(caller, "expected AM or PM", value, pos)
+			}
+			pos += 2
+			continue
+
+		case fieldOffset:
+			offset, length, ok := parseOffset(value[pos:])
+			if !ok {
+				return t, layoutError(caller, "expected UTC offset", value, pos)
+			}
+			v.offset, v.hasOffset = offset, true
+			pos += length
+			continue
+
+		case fieldZoneName:
+			start := pos
+			for pos < len(value) && (value[pos] >= 'A' && value[pos] <= 'Z' || value[pos] >= 'a' && value[pos] <= 'z') {
+				pos++
+			}
+			if pos == start {
+				return t, layoutError(caller, "expected zone name", value, pos)
+			}
+			v.zoneName = value[start:pos]
+			continue
+
+		case fieldFraction:
+			if f.flags&fractionTrim != 0 {
+				// An optional separator and digits
+				if !strings.HasPrefix(value[pos:], f.text) || pos+len(f.text) >= len(value) ||
+					value[pos+len(f.text)] < '0' || value[pos+len(f.text)] > '9' {
+					v.nsec = 0
+					continue
+				}
+				pos += len(f.text)
+			}
+			start := pos
+			for pos < len(value) && pos-start < f.maxDigits() && value[pos] >= '0' && value[pos] <= '9' {
+				pos++
+			}
+			digits := pos - start
+			if digits == 0 || (f.width > 0 && f.flags&(fractionTrim|fractionLenient) == 0 && digits != f.width) {
+				return t, layoutError(caller, "expected fraction of a second", value, start)
+			}
+			n, _ := atoiDigits(value[start:pos])
+			v.nsec = n * intPow(10, 9-digits)
+			continue
+		}
+
+		// Numeric fields
+		if f.pad == ' ' {
+			for pos < len(value) && value[pos] == ' ' {
+				pos++
+			}
+		}
+		start := pos
+		negative := false
+		if (f.kind == fieldUnix || f.kind == fieldYear) && pos < len(value) && (value[pos] == '-' || value[pos] == '+') {
+			negative = value[pos] == '-'
+			pos++
+		}
+		digitStart := pos
+		for pos < len(value) && pos-digitStart < f.maxDigits() && value[pos] >= '0' && value[pos] <= '9' {
+			pos++
+		}
+		if pos == digitStart {
+			return t, layoutError(caller, "expected a number", value, start)
+		}
+		n, convErr := atoiDigits(value[digitStart:pos])
+		if convErr != nil {
+			return t, layoutError(caller, "number out of range", value, start)
+		}
+		if negative {
+			n = -n
+		}
+
+		switch f.kind {
+		case fieldYear:
+			v.year = n
+		case fieldYear2:
+			v.year2 = n
+		case fieldCentury:
+			v.century = n
+		case fieldISOYear:
+			v.isoYear = n
+		case fieldISOYear2:
+			v.isoYear2 = n
+		case fieldMonth:
+			v.month = n
+		case fieldDay:
+			v.day = n
+		case fieldYearDay:
+			v.yday = n
+		case fieldWeekdayISO:
+			v.weekday = n % 7
+			if n < 1 || n > 7 {
+				return t, layoutError(caller, "weekday out of range", value, start)
+			}
+		case fieldWeekday:
+			v.weekday = n
+			if n > 6 {
+				return t, layoutError(caller, "weekday out of range", value, start)
+			}
+		case fieldISOWeek:
+			v.isoWeek = n
+		case fieldWeekSunday:
+			v.weekSunday = n
+		case fieldWeekMonday:
+			v.weekMonday = n
+		case fieldHour:
+			v.hour = n
+		case fieldHour12:
+			v.hour12 = n
+		case fieldMinute:
+			v.min = n
+		case fieldSecond:
+			v.sec = n
+		case fieldUnix:
+			v.unix, v.hasUnix = int64(n), true
+		}
+	}
+
+	if pos < len(value) {
+		return t, layoutError(caller, "extra text", value, pos)
+	}
+
+	return v.time(caller, value, location)
+}
+
+// atoiDigits convert a string of up to 19 ASCII digits to an int
+func atoiDigits(s string) (n int, err error) {
+	for i := 0; i < len(s); i++ {
+		d := int(s[i] - '0')
+		if n > (1<<63-1-d)/10 {
+			return 0, errCannotParseNumber
+		}
+		n = n*10 + d
+	}
+	return
+}
+
+// twoDigitYear expand a two digit year. Values 69-99 are in the 1900s and
+// 0-68 are in the 2000s, as with strptime and Go's time package.
+func twoDigitYear(year2 int) int {
+	if year2 >= 69 {
+		return 1900 + year2
+	}
+	return 2000 + year2
+}
+
+// time build a time from parsed values
+func (v layoutValues) time(caller string, value string, location *time.Location) (t time.Time, err error) {
+	// Work out the location first
+	loc := location
+	utcName := false
+	if v.hasOffset {
+		loc = time.UTC
+		if v.offset != 0 {
+			loc = LocationFromOffset(v.offset)
+		}
+	} else if v.zoneName != "" {
+		switch strings.ToUpper(v.zoneName) {
+		case "UTC", "GMT", "Z", "UT":
+			loc, utcName = time.UTC, true
+		}
+		// Other names are checked against location once the time is known
+	}
+
+	if v.hasUnix {
+		return time.Unix(v.unix, int64(v.nsec)).In(loc), nil
+	}
+
+	// Clock
+	hour, min, sec := v.hour, v.min, v.sec
+	if v.hour12 >= 0 {
+		if v.hour12 < 1 || v.hour12 > 12 {
+			return t, layoutError(caller, "hour out of range", value, -1)
+		}
+		hour = v.hour12 % 12
+		if v.pm == 2 {
+			hour += 12
+		}
+	} else if v.pm == 2 && hour >= 0 && hour < 12 {
+		hour += 12
+	}
+	if hour < 0 {
+		hour = 0
+	}
+	if hour > 23 || min > 59 || sec > 59 {
+		return t, layoutError(caller, "time out of range", value, -1)
+	}
+
+	// Year
+	year := v.year
+	if year < 0 && v.year2 >= 0 {
+		if v.century >= 0 {
+			year = v.century*100 + v.year2
+		} else {
+			year = twoDigitYear(v.year2)
+		}
+	} else if year < 0 && v.century >= 0 {
+		year = v.century * 100
+	}
+	isoYear := v.isoYear
+	if isoYear < 0 && v.isoYear2 >= 0 {
+		isoYear = twoDigitYear(v.isoYear2)
+	}
+
+	weekday := time.Monday
+	if v.weekday >= 0 {
+		weekday = time.Weekday(v.weekday)
+	}
+
+	var date time.Time
+	switch {
+	case isoYear >= 0 && v.isoWeek >= 0 && v.month < 0:
+		if v.isoWeek < 1 || v.isoWeek > 53 {
+			return t, layoutError(caller, "ISO week out of range", value, -1)
+		}
+		date = isoWeekStart(isoYear, v.isoWeek).AddDate(0, 0, (int(weekday)+6)%7)
+		if _, week := date.ISOWeek(); week != v.isoWeek {
+			return t, layoutError(caller, "ISO week out of range", value, -1)
+		}
+	case year >= 0 && v.yday >= 0 && v.month < 0:
+		days := 365
+		if isLeap(year) {
+			days = 366
+		}
+		if v.yday < 1 || v.yday > days {
+			return t, layoutError(caller, "day of year out of range", value, -1)
+		}
+		date = time.Date(year, time.January, v.yday, 0, 0, 0, 0, time.UTC)
+	case year >= 0 && v.month < 0 && v.day < 0 && (v.weekSunday >= 0 || v.weekMonday >= 0):
+		first, week := time.Sunday, v.weekSunday
+		if v.weekMonday >= 0 {
+			first, week = time.Monday, v.weekMonday
+		}
+		jan1 := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		// Day of year (0 based) of the first day of week 1
+		firstDay := (int(first) - int(jan1.Weekday()) + 7) % 7
+		date = jan1.AddDate(0, 0, firstDay+(week-1)*7+(int(weekday)-int(first)+7)%7)
+		if date.Year() != year {
+			return t, layoutError(caller, "week out of range", value, -1)
+		}
+	default:
+		if year < 0 {
+			year = 0
+		}
+		month, day := v.month, v.day
+		if month < 0 {
+			month = 1
+		}
+		if day < 0 {
+			day = 1
+		}
+		if month < 1 || month > 12 || day < 1 || day > daysIn(time.Month(month), year) {
+			return t, layoutError(caller, "date out of range", value, -1)
+		}
+		date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	}
+
+	y, m, d := date.Date()
+	t = time.Date(y, m, d, hour, min, sec, v.nsec, loc)
+
+	// A zone name is only accepted if it is in use by location at the time,
+	// including when location is UTC
+	if v.zoneName != "" && !utcName {
+		if name, _ := t.Zone(); !strings.EqualFold(name, v.zoneName) {
+			return time.Time{}, layoutError(caller, "unknown zone name "+v.zoneName, value, -1)
+		}
+	}
+
+	return
+}
+
+// isoWeekStart get the Monday that starts a week of an ISO year
+func isoWeekStart(isoYear int, week int) time.Time {
+	// January 4 is always in week 1
+	jan4 := time.Date(isoYear, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+
+	return monday.AddDate(0, 0, (week-1)*7)
+}
+
+// layoutCache a bounded cache of compiled layouts keyed by pattern language
+// and pattern. Compiling is cheap but caching keeps repeated formatting with
+// the same pattern from allocating.
+type layoutCache struct {
+	m     sync.Map
+	count int64
+}
+
+// maxCachedLayouts the number of layouts kept before the cache is cleared
+const maxCachedLayouts = 256
+
+func (c *layoutCache) get(pattern string) ([]layoutField, bool) {
+	l, ok := c.m.Load(pattern)
+	if !ok {
+		return nil, false
+	}
+	return l.([]layoutField), true
+}
+
+func (c *layoutCache) put(pattern string, layout []layoutField) {
+	if atomic.AddInt64(&c.count, 1) > maxCachedLayouts {
+		c.m.Range(func(k, _ interface{}) bool {
+			c.m.Delete(k)
+			return true
+		})
+		atomic.StoreInt64(&c.count, 1)
+	}
+	c.m.Store(pattern, layout)
+}
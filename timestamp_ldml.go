@@ -0,0 +1,256 @@
+package timestamp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// ldmlCache compiled LDML patterns
+var ldmlCache layoutCache
+
+// ldmlError make an error for a pattern problem
+func ldmlError(reason string, pattern string, pos int) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.LDML: ").S(reason).S(" '").S(pattern[pos:]).S("'").C('@').D(pos).S(" in pattern ").S(pattern)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// isLDMLLetter is the byte an ASCII letter, which LDML reserves for fields
+func isLDMLLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// ldmlNumeric a numeric field where the count of letters is the minimum
+// width, with one letter meaning no padding
+func ldmlNumeric(kind fieldKind, count int, max int) (f layoutField, ok bool) {
+	if count > max {
+		return f, false
+	}
+	f.kind = kind
+	if count > 1 {
+		f.width, f.pad = count, '0'
+	}
+	return f, true
+}
+
+// ldmlField get the layout field for a run of count copies of letter c
+func ldmlField(c byte, count int) (f layoutField, ok bool) {
+	switch c {
+	case 'y', 'u':
+		if count == 2 {
+			return layoutField{kind: fieldYear2, width: 2, pad: '0'}, true
+		}
+		return ldmlNumeric(fieldYear, count, 9)
+	case 'Y':
+		if count == 2 {
+			return layoutField{kind: fieldISOYear2, width: 2, pad: '0'}, true
+		}
+		return ldmlNumeric(fieldISOYear, count, 9)
+	case 'M', 'L':
+		switch count {
+		case 1, 2:
+			return ldmlNumeric(fieldMonth, count, 2)
+		case 3:
+			return layoutField{kind: fieldMonthAbbr}, true
+		case 4:
+			return layoutField{kind: fieldMonthName}, true
+		}
+	case 'd':
+		return ldmlNumeric(fieldDay, count, 2)
+	case 'D':
+		return ldmlNumeric(fieldYearDay, count, 3)
+	case 'E':
+		switch {
+		case count <= 3:
+			return layoutField{kind: fieldWeekdayAbbr}, true
+		case count == 4:
+			return layoutField{kind: fieldWeekdayName}, true
+		}
+	case 'e', 'c':
+		switch count {
+		case 1, 2:
+			return ldmlNumeric(fieldWeekdayISO, count, 2)
+		case 3:
+			return layoutField{kind: fieldWeekdayAbbr}, true
+		case 4:
+			return layoutField{kind: fieldWeekdayName}, true
+		}
+	case 'w':
+		return ldmlNumeric(fieldISOWeek, count, 2)
+	case 'a':
+		if count <= 3 {
+			return layoutField{kind: fieldAMPM}, true
+		}
+	case 'h':
+		return ldmlNumeric(fieldHour12, count, 2)
+	case 'H':
+		return ldmlNumeric(fieldHour, count, 2)
+	case 'm':
+		return ldmlNumeric(fieldMinute, count, 2)
+	case 's':
+		return ldmlNumeric(fieldSecond, count, 2)
+	case 'S':
+		if count <= 9 {
+			return layoutField{kind: fieldFraction, width: count}, true
+		}
+	case 'X', 'x':
+		flags := 0
+		if c == 'X' {
+			flags = offsetZ
+		}
+		switch count {
+		case 1:
+			return layoutField{kind: fieldOffset, flags: flags | offsetHours}, true
+		case 2:
+			return layoutField{kind: fieldOffset, flags: flags}, true
+		case 3:
+			return layoutField{kind: fieldOffset, flags: flags | offsetColon}, true
+		case 4:
+			return layoutField{kind: fieldOffset, flags: flags | offsetSeconds}, true
+		case 5:
+			return layoutField{kind: fieldOffset, flags: flags | offsetColon | offsetSeconds}, true
+		}
+	case 'Z':
+		switch count {
+		case 1, 2, 3:
+			return layoutField{kind: fieldOffset}, true
+		case 5:
+			return layoutField{kind: fieldOffset, flags: offsetZ | offsetColon | offsetSeconds}, true
+		}
+	case 'z':
+		if count <= 3 {
+			return layoutField{kind: fieldZoneName}, true
+		}
+	}
+
+	return f, false
+}
+
+// compileLDML compile a Unicode LDML pattern into a layout
+func compileLDML(pattern string) (layout []layoutField, err error) {
+	if l, ok := ldmlCache.get(pattern); ok {
+		return l, nil
+	}
+
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+
+		switch {
+		case c == '\'':
+			// Two quotes are a literal quote, inside or outside quoted text
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				layout = appendLiteral(layout, "'")
+				i += 2
+				continue
+			}
+			start := i
+			i++
+			text := ""
+			for {
+				if i >= len(pattern) {
+					return nil, ldmlError("unterminated quote", pattern, start)
+				}
+				if pattern[i] == '\'' {
+					if i+1 < len(pattern) && pattern[i+1] == '\'' {
+						text += "'"
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				j := i
+				for j < len(pattern) && pattern[j] != '\'' {
+					j++
+				}
+				text += pattern[i:j]
+				i = j
+			}
+			layout = appendLiteral(layout, text)
+
+		case isLDMLLetter(c):
+			start := i
+			for i < len(pattern) && pattern[i] == c {
+				i++
+			}
+			f, ok := ldmlField(c, i-start)
+			if !ok {
+				return nil, ldmlError("unsupported pattern letters", pattern, start)
+			}
+			layout = append(layout, f)
+
+		default:
+			j := i
+			for j < len(pattern) && pattern[j] != '\'' && !isLDMLLetter(pattern[j]) {
+				j++
+			}
+			layout = appendLiteral(layout, pattern[i:j])
+			i = j
+		}
+	}
+
+	ldmlCache.put(pattern, layout)
+
+	return
+}
+
+// FormatLDML format a time using a Unicode LDML pattern, the pattern syntax
+// used by Java's DateTimeFormatter and ICU, such as
+// yyyy-MM-dd'T'HH:mm:ss.SSSXXX. The result is in the location of t and is
+// produced by the same formatting engine as ISO8601Msec.
+//
+// Supported pattern letters:
+//
+//	y, u      year; yy is two digits, other counts are the minimum width
+//	Y         ISO-8601 week based year; YY is two digits
+//	M, L      month; M or MM is numeric, MMM abbreviated, MMMM full
+//	d         day of month
+//	D         day of year
+//	E         weekday; E to EEE abbreviated, EEEE full
+//	e, c      weekday; 1 or 2 letters numeric with Monday 1, 3 abbreviated, 4 full
+//	w         ISO-8601 week of week based year
+//	a         AM or PM
+//	h         hour 1-12
+//	H         hour 0-23
+//	m         minute
+//	s         second
+//	S         fraction of a second, one digit per letter
+//	X         offset with Z for zero: X +hh[mm], XX +hhmm, XXX +hh:mm,
+//	          XXXX +hhmm[ss], XXXXX +hh:mm[:ss]
+//	x         offset as for X but never Z
+//	Z         offset; Z to ZZZ +hhmm, ZZZZZ +hh:mm[:ss] or Z
+//	z         zone abbreviation; z to zzz
+//
+// Text in single quotes is literal and two single quotes are a literal quote.
+// Other characters that are not ASCII letters are literal. Week based fields
+// follow ISO-8601 rules rather than locale rules. Any other letters are an
+// error.
+func FormatLDML(t time.Time, pattern string) (string, error) {
+	layout, err := compileLDML(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	return formatLayout(t, layout), nil
+}
+
+// ParseLDML parse a value using a Unicode LDML pattern. The supported letters
+// are those documented for FormatLDML. Fraction fields must have exactly the
+// number of digits given by the count of S letters. If the value has no
+// offset or zone location is used. A z zone name is checked as Strptime
+// checks %Z.
+func ParseLDML(value string, pattern string, location *time.Location) (time.Time, error) {
+	layout, err := compileLDML(pattern)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return parseLayout("ParseLDML", value, layout, location)
+}
@@ -0,0 +1,105 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestFormatLDML(t *testing.T) {
+	is := is.New(t)
+
+	ist := time.FixedZone("IST", 5*60*60+30*60)
+	ts := time.Date(2021, 1, 3, 17, 4, 5, 123456789, ist)
+	utc := ts.In(time.UTC)
+
+	tests := []struct {
+		ts       time.Time
+		pattern  string
+		expected string
+	}{
+		{ts, "yyyy-MM-dd'T'HH:mm:ss.SSSXXX", "2021-01-03T17:04:05.123+05:30"},
+		{utc, "yyyy-MM-dd'T'HH:mm:ss.SSSXXX", "2021-01-03T11:34:05.123Z"},
+		{utc, "yyyy-MM-dd'T'HH:mm:ss.SSSxxx", "2021-01-03T11:34:05.123+00:00"},
+		{ts, "yyyyMMdd'T'HHmmssX", "20210103T170405+0530"},
+		{utc, "yyyyMMdd'T'HHmmssX", "20210103T113405Z"},
+		{ts, "yy-M-d h:m:s a", "21-1-3 5:4:5 PM"},
+		{ts, "EEE, dd MMM yyyy HH:mm:ss Z", "Sun, 03 Jan 2021 17:04:05 +0530"},
+		{ts, "EEEE MMMM d ZZZZZ z", "Sunday January 3 +05:30 IST"},
+		{ts, "YYYY-'W'ww-e D", "2020-W53-7 3"},
+		{ts, "HH:mm:ss.SSSSSS", "17:04:05.123456"},
+		{ts, "'o''clock' h ''", "o'clock 5 '"},
+		{ts, "x xx xxx", "+0530 +0530 +05:30"},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.FormatLDML(test.ts, test.pattern)
+		is.NoErr(err) // Pattern should be supported
+		t.Logf("pattern %q got %q", test.pattern, got)
+		is.Equal(got, test.expected)
+	}
+
+	for _, pattern := range []string{"GGGG yyyy", "yyyy-MM-dd'T", "MMMMM", "zzzz", "ZZZZ", "kk:mm"} {
+		_, err := timestamp.FormatLDML(ts, pattern)
+		t.Logf("pattern %q error %v", pattern, err)
+		is.True(err != nil) // Unsupported letters should be an error
+	}
+}
+
+func TestParseLDML(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err) // Location should load
+
+	tests := []struct {
+		value    string
+		pattern  string
+		expected time.Time
+	}{
+		{"2021-01-03T17:04:05.123+05:30", "yyyy-MM-dd'T'HH:mm:ss.SSSXXX",
+			time.Date(2021, 1, 3, 11, 34, 5, 123000000, time.UTC)},
+		{"2021-01-03T11:34:05.123Z", "yyyy-MM-dd'T'HH:mm:ss.SSSXXX",
+			time.Date(2021, 1, 3, 11, 34, 5, 123000000, time.UTC)},
+		{"03/01/2021 5:04 PM", "dd/MM/yyyy h:mm a", time.Date(2021, 1, 3, 17, 4, 0, 0, toronto)},
+		{"Sun, 03 Jan 2021 17:04:05 -0500", "EEE, dd MMM yyyy HH:mm:ss Z",
+			time.Date(2021, 1, 3, 22, 4, 5, 0, time.UTC)},
+		{"2020-W53-7", "YYYY-'W'ww-e", time.Date(2021, 1, 3, 0, 0, 0, 0, toronto)},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.ParseLDML(test.value, test.pattern, toronto)
+		is.NoErr(err) // Value should parse
+		t.Logf("value %q pattern %q got %v", test.value, test.pattern, got)
+		is.True(got.Equal(test.expected)) // Result should match expected
+	}
+
+	// Fraction width is exact
+	_, err = timestamp.ParseLDML("17:04:05.12", "HH:mm:ss.SSS", toronto)
+	is.True(err != nil) // Too few fraction digits should be an error
+
+	// A zone name must be one the location uses, even for UTC
+	_, err = timestamp.ParseLDML("2024-01-02 03:04:05 EST", "yyyy-MM-dd HH:mm:ss z", time.UTC)
+	t.Log(err)
+	is.True(err != nil) // EST is not used by UTC
+	got, err := timestamp.ParseLDML("2024-01-02 03:04:05 UTC", "yyyy-MM-dd HH:mm:ss z", toronto)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+}
+
+// ISO8601Msec is produced by the layout engine and must match time.Format
+func TestISO8601MsecEngine(t *testing.T) {
+	is := is.New(t)
+
+	locations := []*time.Location{
+		time.UTC,
+		time.FixedZone("", -3*60*60-30*60),
+		time.FixedZone("", 5*60*60+45*60),
+	}
+	for _, location := range locations {
+		ts := time.Date(2006, 1, 2, 15, 4, 5, 987654321, location)
+		is.Equal(timestamp.ISO8601Msec(ts), ts.Format("2006-01-02T15:04:05.000-07:00"))
+	}
+}
@@ -0,0 +1,269 @@
+package timestamp
+
+import (
+	"strings"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// LayoutProblem a problem found in a Go layout by LintLayout
+type LayoutProblem struct {
+	Position int    // byte offset of the problem in the layout, -1 for the whole layout
+	Text     string // the part of the layout with the problem
+	Message  string // what is wrong
+}
+
+// String describe the problem
+func (p LayoutProblem) String() string {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	if p.Position >= 0 {
+		xfmtBuf.C('\'').S(p.Text).C('\'').C('@').D(p.Position).S(": ")
+	}
+	xfmtBuf.S(p.Message)
+
+	return string(xfmtBuf.Bytes())
+}
+
+// lintFieldClass the group a field belongs to when looking for duplicates
+var lintFieldClass = map[fieldKind]string{
+	fieldYear: "year", fieldYear2: "year",
+	fieldMonth: "month", fieldMonthAbbr: "month", fieldMonthName: "month",
+	fieldDay: "day", fieldYearDay: "day",
+	fieldWeekdayAbbr: "weekday", fieldWeekdayName: "weekday",
+	fieldHour: "hour", fieldHour12: "hour",
+	fieldMinute: "minute", fieldSecond: "second", fieldFraction: "fraction of a second",
+	fieldAMPM: "AM/PM", fieldAMPMLower: "AM/PM",
+	fieldOffset: "UTC offset", fieldZoneName: "zone name",
+}
+
+// lintSamples instants used to round trip layouts. They cover morning and
+// afternoon, days after the 12th, leap days, fractions and offsets with
+// minutes.
+var lintSamples = []time.Time{
+	time.Date(2021, 1, 13, 17, 4, 5, 123456789, time.FixedZone("IST", 5*60*60+30*60)),
+	time.Date(2019, 11, 28, 4, 9, 7, 500000000, time.FixedZone("NST", -(3*60*60+30*60))),
+	time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC),
+	time.Date(1999, 12, 31, 23, 59, 59, 0, time.UTC),
+}
+
+// lintField a layout field with its position in the layout
+type lintField struct {
+	layoutField
+	pos  int
+	text string
+}
+
+// isPatternLetterRun does text look like pattern letters such as YYYY, MM,
+// DD or HHmmss
+func isPatternLetterRun(text string) bool {
+	if len(text) < 2 {
+		return false
+	}
+	for i := 0; i < len(text); {
+		if !strings.ContainsRune("yYMDdHhmsS", rune(text[i])) {
+			return false
+		}
+		j := i
+		for j < len(text) && text[j] == text[i] {
+			j++
+		}
+		if j-i < 2 {
+			return false
+		}
+		i = j
+	}
+	return true
+}
+
+// lintLiteral look for text in a literal that was probably meant to be a
+// field
+func lintLiteral(text string, pos int) (problems []LayoutProblem) {
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c >= '0' && c <= '9':
+			j := i
+			for j < len(text) && text[j] >= '0' && text[j] <= '9' {
+				j++
+			}
+			problems = append(problems, LayoutProblem{pos + i, text[i:j],
+				"digits that are not a reference value are copied literally; the reference time is Mon Jan 2 15:04:05 MST 2006"})
+			i = j
+		case c == '%' && i+1 < len(text) && isLDMLLetter(text[i+1]):
+			problems = append(problems, LayoutProblem{pos + i, text[i : i+2],
+				"strftime directive in a Go layout; use TranslateLayout to convert strftime patterns"})
+			i += 2
+		case isLDMLLetter(c):
+			j := i
+			for j < len(text) && isLDMLLetter(text[j]) {
+				j++
+			}
+			word := text[i:j]
+			switch {
+			case word == "AM" || word == "am":
+				problems = append(problems, LayoutProblem{pos + i, word,
+					"AM is not a reference value; use PM or pm for the AM/PM marker"})
+			case isPatternLetterRun(word):
+				problems = append(problems, LayoutProblem{pos + i, word,
+					"pattern letters are not reference values; Go layouts use 2006 01 02 15 04 05"})
+			}
+			i = j
+		default:
+			i++
+		}
+	}
+
+	return
+}
+
+// LintLayout check a Go reference layout, such as those in
+// nonISOTimeFormats, for common mistakes. An empty result means no problems
+// were found.
+//
+// Problems reported:
+//
+//   - text that is not a reference value but looks like it was meant to be,
+//     such as YYYY-MM-DD, %Y, AM or digits like 2007
+//   - a 12 hour clock without PM, or PM with a 24 hour clock
+//   - a day between a year and a month, as in 2006-02-01
+//   - a field that appears more than once
+//   - an offset such as -07 or Z07 that drops minutes
+//   - a layout that does not round trip sample instants through time.Format
+//     and time.Parse
+func LintLayout(layout string) (problems []LayoutProblem) {
+	var fields []lintField
+
+	rest, pos := layout, 0
+	for rest != "" {
+		prefix, f, suffix, found := nextGoChunk(rest)
+		if prefix != "" {
+			problems = append(problems, lintLiteral(prefix, pos)...)
+			pos += len(prefix)
+		}
+		if !found {
+			break
+		}
+		n := len(rest) - len(prefix) - len(suffix)
+		fields = append(fields, lintField{f, pos, rest[len(prefix) : len(prefix)+n]})
+		pos += n
+		rest = suffix
+	}
+
+	present := map[fieldKind]int{}
+	classes := map[string]lintField{}
+	for _, f := range fields {
+		present[f.kind]++
+		class := lintFieldClass[f.kind]
+		if first, ok := classes[class]; ok {
+			problems = append(problems, LayoutProblem{f.pos, f.text,
+				class + " appears more than once, first as " + first.text})
+			continue
+		}
+		classes[class] = f
+
+		if f.kind == fieldOffset && f.flags&offsetNoMinutes != 0 {
+			problems = append(problems, LayoutProblem{f.pos, f.text,
+				"offset without minutes loses the minutes of offsets like +05:30; use " + f.text + ":00"})
+		}
+	}
+
+	hasAMPM := present[fieldAMPM]+present[fieldAMPMLower] > 0
+	for _, f := range fields {
+		switch {
+		case f.kind == fieldHour12 && !hasAMPM:
+			problems = append(problems, LayoutProblem{f.pos, f.text,
+				"12 hour clock without PM can't tell morning from afternoon; use 15 for a 24 hour clock"})
+		case (f.kind == fieldAMPM || f.kind == fieldAMPMLower) && present[fieldHour] > 0:
+			problems = append(problems, LayoutProblem{f.pos, f.text,
+				"PM with a 24 hour clock; use 03 or 3 for a 12 hour clock"})
+		}
+	}
+
+	// Year then day then month is almost always a swap of 01 and 02
+	yearPos, dayPos, monthPos := -1, -1, -1
+	for i, f := range fields {
+		switch f.kind {
+		case fieldYear, fieldYear2:
+			yearPos = i
+		case fieldDay:
+			dayPos = i
+		case fieldMonth:
+			monthPos = i
+		}
+	}
+	if yearPos >= 0 && dayPos > yearPos && monthPos > dayPos {
+		problems = append(problems, LayoutProblem{fields[dayPos].pos, fields[dayPos].text,
+			"day before month after a year; the reference date is 2006-01-02 so 01 is the month and 02 the day"})
+	}
+
+	if p, ok := lintRoundTrip(layout, fields, present); !ok {
+		problems = append(problems, p)
+	}
+
+	return
+}
+
+// lintRoundTrip format and parse sample instants with a layout. If the layout
+// has a full date and time the parsed value is compared with the sample,
+// otherwise formatting the parsed value must give the same text.
+func lintRoundTrip(layout string, fields []lintField, present map[fieldKind]int) (problem LayoutProblem, ok bool) {
+	complete := present[fieldYear]+present[fieldYear2] > 0 &&
+		present[fieldMonth]+present[fieldMonthAbbr]+present[fieldMonthName] > 0 &&
+		present[fieldDay] > 0 && present[fieldHour]+present[fieldHour12] > 0 && present[fieldMinute] > 0
+
+	// The precision the layout keeps
+	precision := time.Minute
+	if present[fieldSecond] > 0 {
+		precision = time.Second
+	}
+	hasOffset := false
+	for _, f := range fields {
+		switch f.kind {
+		case fieldFraction:
+			precision = time.Nanosecond
+			for i := f.width; i < 9; i++ {
+				precision *= 10
+			}
+		case fieldOffset:
+			hasOffset = true
+		}
+	}
+
+	for _, sample := range lintSamples {
+		text := sample.Format(layout)
+		parsed, err := time.Parse(layout, text)
+
+		xfmtBuf := new(xfmt.Buffer)
+		switch {
+		case err != nil:
+			xfmtBuf.S("layout does not parse its own output ").Q(text).S(": ").S(err.Error())
+		case complete && hasOffset && !parsed.Equal(sample.Truncate(precision)):
+			xfmtBuf.S("round trip of ").S(sample.Format(time.RFC3339Nano)).S(" through ").Q(text).
+				S(" gives ").S(parsed.Format(time.RFC3339Nano))
+		case complete && !hasOffset && !sameWallClock(parsed, sample.Truncate(precision)):
+			xfmtBuf.S("round trip of ").S(sample.Format("2006-01-02T15:04:05.999999999")).S(" through ").Q(text).
+				S(" gives ").S(parsed.Format("2006-01-02T15:04:05.999999999"))
+		case !complete && parsed.Format(layout) != text:
+			xfmtBuf.S("round trip of ").Q(text).S(" gives ").Q(parsed.Format(layout))
+		default:
+			continue
+		}
+
+		return LayoutProblem{-1, layout, string(xfmtBuf.Bytes())}, false
+	}
+
+	return problem, true
+}
+
+// sameWallClock do two times show the same date and time of day, ignoring
+// their locations
+func sameWallClock(a, b time.Time) bool {
+	y1, m1, d1 := a.Date()
+	y2, m2, d2 := b.Date()
+	h1, min1, s1 := a.Clock()
+	h2, min2, s2 := b.Clock()
+
+	return y1 == y2 && m1 == m2 && d1 == d2 && h1 == h2 && min1 == min2 && s1 == s2 && a.Nanosecond() == b.Nanosecond()
+}
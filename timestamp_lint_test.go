@@ -0,0 +1,77 @@
+package timestamp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestLintLayoutClean(t *testing.T) {
+	is := is.New(t)
+
+	layouts := []string{
+		time.RFC3339, time.RFC3339Nano, time.RFC1123, time.RFC1123Z, time.RFC822Z, time.RFC850,
+		time.ANSIC, time.UnixDate, time.Kitchen, time.Stamp, time.StampMilli, time.DateTime,
+		"Mon, 02 Jan 2006 15:04:05 GMT",
+		"Mon, 02 Jan 2006 15:04:05 -0700",
+		"Monday, 02-Jan-2006 15:04:05",
+		"02 Jan 06 15:04 -0700",
+		"2006-01-02 15-04-05",
+		"20060102150405",
+		"20060102",
+		"01/02/2006",
+		"1/2/2006",
+		"2006-01-02T15:04:05.000Z07:00",
+		"2006-01-02T15:04:05.999999999-07:00",
+		"Jan 2, 2006 at 3:04pm (MST)",
+	}
+
+	for _, layout := range layouts {
+		problems := timestamp.LintLayout(layout)
+		for _, p := range problems {
+			t.Logf("%q: %v", layout, p)
+		}
+		is.Equal(len(problems), 0) // Layout should have no problems
+	}
+}
+
+func TestLintLayoutProblems(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		layout   string
+		text     string
+		position int
+		message  string
+	}{
+		{"YYYY-MM-DD", "YYYY", 0, "pattern letters"},
+		{"YYYY-MM-DD", "MM", 5, "pattern letters"},
+		{"yyyyMMdd HHmmss", "HHmmss", 9, "pattern letters"},
+		{"%Y-%m-%d", "%Y", 0, "strftime directive"},
+		{"2006-01-02 03:04 AM", "AM", 17, "AM is not"},
+		{"2006-01-02 15:04:05 PM", "PM", 20, "PM with a 24 hour clock"},
+		{"2006-01-02 03:04:05", "03", 11, "12 hour clock without PM"},
+		{"2006-02-01", "02", 5, "day before month"},
+		{"2006-01-01", "01", 8, "month appears more than once"},
+		{"Jan 01 2006", "01", 4, "month appears more than once"},
+		{"15:04:05.000Z07", "Z07", 12, "offset without minutes"},
+		{"2007-01-02", "007", 1, "digits that are not a reference value"},
+		{"2006-01-02 15:04:05.000Z07", "2006-01-02 15:04:05.000Z07", -1, "round trip"},
+		{"2006-01-02 03:04:05", "2006-01-02 03:04:05", -1, "round trip"},
+	}
+
+	for _, test := range tests {
+		problems := timestamp.LintLayout(test.layout)
+		found := false
+		for _, p := range problems {
+			t.Logf("%q: %v", test.layout, p)
+			if p.Text == test.text && p.Position == test.position && strings.Contains(p.Message, test.message) {
+				found = true
+			}
+		}
+		is.True(found) // Problem should be reported
+	}
+}
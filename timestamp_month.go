@@ -0,0 +1,115 @@
+package timestamp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/utility"
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// MonthOverflow what month arithmetic does when the day of the month does not
+// exist in the resulting month, such as Jan 31 plus one month
+type MonthOverflow int
+
+// Month overflow behaviours
+const (
+	MonthClamp    MonthOverflow = iota // use the last day of the month, Feb 29
+	MonthRollover                      // spill into the next month, Mar 2, as time.AddDate does
+	MonthError                         // return an error
+)
+
+// AddMonths add months to a time keeping the wall clock, with overflow
+// deciding what happens when the day is past the end of the resulting month.
+// An error is only returned with MonthError.
+func AddMonths(t time.Time, months int, overflow MonthOverflow) (time.Time, error) {
+	result, ok := addMonths(t, months, overflow)
+	if !ok {
+		return time.Time{}, monthError("timestamp.AddMonths", t, months)
+	}
+	return result, nil
+}
+
+// AddYears add years to a time keeping the wall clock, with overflow deciding
+// what happens to Feb 29 in a year that is not a leap year
+func AddYears(t time.Time, years int, overflow MonthOverflow) (time.Time, error) {
+	result, ok := addMonths(t, years*12, overflow)
+	if !ok {
+		return time.Time{}, monthError("timestamp.AddYears", t, years*12)
+	}
+	return result, nil
+}
+
+// IsLastDayOfMonth is t on the last day of its month in its location
+func IsLastDayOfMonth(t time.Time) bool {
+	year, month, day := t.Date()
+	return day == daysIn(month, year)
+}
+
+// AddMonthsSticky add months so that the last day of a month stays the last
+// day of the month. Jan 31, Feb 29 and Apr 30 all move to May 31 with the
+// months needed to get there. Other days are clamped as with MonthClamp.
+func AddMonthsSticky(t time.Time, months int) time.Time {
+	result, _ := addMonths(t, months, MonthClamp)
+	if !IsLastDayOfMonth(t) {
+		return result
+	}
+	year, month, _ := result.Date()
+	return result.AddDate(0, 0, daysIn(month, year)-result.Day())
+}
+
+// MonthSequence get count times every n months from start, each computed from
+// start rather than from the time before it so a clamped day does not stick.
+// Jan 31 monthly gives Feb 29, Mar 31 and Apr 30 rather than Feb 29, Mar 29
+// and Apr 29. With stickyEnd a start on the last day of a month gives the last
+// day of every month, so Feb 29 monthly gives Mar 31 and Apr 30.
+func MonthSequence(start time.Time, every, count int, stickyEnd bool) []time.Time {
+	times := make([]time.Time, 0, max(count, 0))
+	for k := 0; k < count; k++ {
+		if stickyEnd {
+			times = append(times, AddMonthsSticky(start, k*every))
+			continue
+		}
+		t, _ := addMonths(start, k*every, MonthClamp)
+		times = append(times, t)
+	}
+	return times
+}
+
+// addMonths add months to a time keeping the wall clock. The month is
+// normalized into the year and the day checked against the length of the new
+// month, which is then clamped, rolled over or rejected. The result is false
+// only for MonthError with a day past the end of the month.
+func addMonths(t time.Time, months int, overflow MonthOverflow) (time.Time, bool) {
+	year, month, day := t.Date()
+	hour, min, sec := t.Clock()
+
+	y, m := utility.Norm(int64(year), int64(month)-1+int64(months), 12)
+	newYear, newMonth := int(y), time.Month(m+1)
+
+	if last := daysIn(newMonth, newYear); day > last {
+		switch overflow {
+		case MonthClamp:
+			day = last
+		case MonthError:
+			return time.Time{}, false
+		}
+		// With MonthRollover time.Date carries the extra days into the next
+		// month just as time.AddDate does
+	}
+
+	return time.Date(newYear, newMonth, day, hour, min, sec, t.Nanosecond(), t.Location()), true
+}
+
+// monthError make an error for a day that is past the end of the month it is
+// moved to
+func monthError(caller string, t time.Time, months int) error {
+	target, _ := addMonths(t, months, MonthClamp)
+
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S(caller).S(": day ").D(t.Day()).S(" past end of month ").S(target.Format("2006-01")).
+		S(" in input ").S(t.Format(time.RFC3339Nano))
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
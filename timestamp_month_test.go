@@ -0,0 +1,80 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestAddMonths(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	jan31 := time.Date(2024, 1, 31, 9, 30, 0, 0, toronto)
+
+	tests := []struct {
+		t        time.Time
+		months   int
+		overflow timestamp.MonthOverflow
+		expected string
+	}{
+		{jan31, 1, timestamp.MonthClamp, "2024-02-29T09:30:00-05:00"},
+		{jan31, 1, timestamp.MonthRollover, "2024-03-02T09:30:00-05:00"},
+		{jan31, 13, timestamp.MonthClamp, "2025-02-28T09:30:00-05:00"},
+		{jan31, 2, timestamp.MonthError, "2024-03-31T09:30:00-04:00"},
+		{jan31, -2, timestamp.MonthClamp, "2023-11-30T09:30:00-05:00"},
+		{jan31, -14, timestamp.MonthRollover, "2022-12-01T09:30:00-05:00"},
+		{time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), 0, timestamp.MonthError, "2024-03-15T00:00:00Z"},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.AddMonths(test.t, test.months, test.overflow)
+		is.NoErr(err)                                     // Should add without error
+		is.Equal(got.Format(time.RFC3339), test.expected) // Time should match
+	}
+
+	// Rollover matches time.AddDate
+	for months := -24; months <= 24; months++ {
+		got, err := timestamp.AddMonths(jan31, months, timestamp.MonthRollover)
+		is.NoErr(err)
+		is.True(got.Equal(jan31.AddDate(0, months, 0))) // Should match AddDate
+	}
+
+	_, err = timestamp.AddMonths(jan31, 1, timestamp.MonthError)
+	is.True(err != nil) // Feb 31 should be rejected
+	t.Log(err)
+
+	leapDay := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	got, err := timestamp.AddYears(leapDay, 1, timestamp.MonthClamp)
+	is.NoErr(err)
+	is.Equal(got.Format("2006-01-02"), "2025-02-28")
+	got, err = timestamp.AddYears(leapDay, 4, timestamp.MonthError)
+	is.NoErr(err)
+	is.Equal(got.Format("2006-01-02"), "2028-02-29")
+	_, err = timestamp.AddYears(leapDay, -1, timestamp.MonthError)
+	is.True(err != nil) // Feb 29 2023 should be rejected
+}
+
+func TestMonthSequence(t *testing.T) {
+	is := is.New(t)
+
+	jan31 := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	feb29 := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	jan30 := time.Date(2024, 1, 30, 0, 0, 0, 0, time.UTC)
+
+	is.Equal(formatTimes(timestamp.MonthSequence(jan31, 1, 4, false), "2006-01-02"), []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"})
+	is.Equal(formatTimes(timestamp.MonthSequence(feb29, 1, 4, false), "2006-01-02"), []string{"2024-02-29", "2024-03-29", "2024-04-29", "2024-05-29"})
+	is.Equal(formatTimes(timestamp.MonthSequence(feb29, 1, 4, true), "2006-01-02"), []string{"2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31"})
+	is.Equal(formatTimes(timestamp.MonthSequence(jan30, 1, 3, true), "2006-01-02"), []string{"2024-01-30", "2024-02-29", "2024-03-30"})
+	is.Equal(formatTimes(timestamp.MonthSequence(feb29, 12, 3, true), "2006-01-02"), []string{"2024-02-29", "2025-02-28", "2026-02-28"})
+	is.Equal(formatTimes(timestamp.MonthSequence(jan31, 3, 3, false), "2006-01-02"), []string{"2024-01-31", "2024-04-30", "2024-07-31"})
+	is.Equal(len(timestamp.MonthSequence(jan31, 1, 0, false)), 0)
+
+	is.True(timestamp.IsLastDayOfMonth(feb29))
+	is.True(!timestamp.IsLastDayOfMonth(jan30))
+	is.Equal(timestamp.AddMonthsSticky(time.Date(2023, 4, 30, 0, 0, 0, 0, time.UTC), 1).Format("2006-01-02"), "2023-05-31")
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/imarsman/timestamp/pkg/xfmt"
 )
 
+var reUSDate *regexp.Regexp
 var reDigits *regexp.Regexp
 var timeFormats = []string{} // A slice of time formats to be used if ISO parsing fails
 var locationAtomic atomic.Value
@@ -78,6 +79,7 @@ var nonISOTimeFormats = []string{
 
 func init() {
 	reDigits = regexp.MustCompile(`^\d+\.?\d+$`)
+	reUSDate = regexp.MustCompile(`^\d{1,2}/\d{1,2}/\d{4}$`)
 	timeFormats = append(timeFormats, nonISOTimeFormats...)
 	// A cache for zones tied to offsets to save quite a bit of time and 3
 	// allocations needed to get a fixed zone.
@@ -191,6 +193,21 @@ func ParseISOInLocation(timeStr string, location *time.Location) (time.Time, err
 //
 // Can't inline due to use of range but it's too complex anyway.
 func parseTimestamp(timeStr string, location *time.Location, isoOnly bool) (t time.Time, err error) {
+	t, _, err = parseTimestampFormat(timeStr, location, isoOnly)
+	return
+}
+
+// Formats reported by parseTimestampFormat in addition to indexes into
+// nonISOTimeFormats
+const (
+	matchedISO  = -2 // parsed as ISO-8601
+	matchedUnix = -1 // parsed as a Unix timestamp
+)
+
+// parseTimestampFormat parse a timestamp and report the format that matched,
+// either matchedISO, matchedUnix or the index of the layout in
+// nonISOTimeFormats.
+func parseTimestampFormat(timeStr string, location *time.Location, isoOnly bool) (t time.Time, matched int, err error) {
 	timeStr = strings.TrimSpace(timeStr)
 	var original string = timeStr
 
@@ -211,10 +228,13 @@ func parseTimestamp(timeStr string, location *time.Location, isoOnly bool) (t ti
 
 	// Try ISO parsing first. The lexer is tolerant of some inconsistency in
 	// format that is not ISO-8601 compliant, such as dashes where there should
-	// be colons and a space instead of a T to separate date and time.
-	if isTS == false {
+	// be colons and a space instead of a T to separate date and time. It would
+	// also take the digits of a US style date such as 01/02/2006 as a year and
+	// month so those are left for the layouts.
+	if isTS == false && reUSDate.MatchString(timeStr) == false {
 		t, err = ParseISOTimestamp(timeStr, location)
 		if err == nil {
+			matched = matchedISO
 			return
 		}
 	}
@@ -242,15 +262,22 @@ func parseTimestamp(timeStr string, location *time.Location, isoOnly bool) (t ti
 		}
 
 		t = t.In(location)
+		matched = matchedUnix
 		return
 	}
 
 	// If not a unix type timestamp try alternate non-iso timestamp formats
 	s := nonISOTimeFormats
-	for _, format := range s {
-		// If no zone in timestamp use location
-		t, err = time.ParseInLocation(format, original, location)
+	for i, format := range s {
+		// If no zone in timestamp use location. A GMT suffix is literal text
+		// to Go so the zone has to be set here.
+		formatLocation := location
+		if strings.HasSuffix(format, " GMT") {
+			formatLocation = time.UTC
+		}
+		t, err = time.ParseInLocation(format, original, formatLocation)
 		if err == nil {
+			matched = i
 			return
 		}
 	}
@@ -329,8 +356,8 @@ func ParseUnixTS(timeStr string) (time.Time, error) {
 			toSend := timeStr
 			// Break it into a format that has a period between second and
 			// millisecond portions for the function.
-			if timeStrLength > 10 {
-				sec, nsec := timeStr[0:10], timeStr[11:timeStrLength-1]
+			if timeStrLength > 10 && !strings.Contains(timeStr, ".") {
+				sec, nsec := timeStr[0:10], timeStr[10:]
 
 				// Avoid heap allocation
 				xfmtBuf := new(xfmt.Buffer)
@@ -360,11 +387,70 @@ func ParseUnixTS(timeStr string) (time.Time, error) {
 	return time.Time{}, errors.New(BytesToString(b...))
 }
 
+// Define sections that are constant. Use iota since the incrementing values
+// correspond to the incremental section processing and give each const a
+// separate value.
+const (
+	emptySection     int = iota // value for empty section
+	yearSection                 // year - four digits
+	monthSection                // month - 2 digits
+	daySection                  // day - 2 digits
+	hourSection                 // hour - 2 digits
+	minuteSection               // minute - 2 digits
+	secondSection               // second - 2 digits
+	subsecondSection            // subsecond 1-9 digits
+	zoneSection                 // zone +/-HHMM or Z
+	afterSection                // after - when done
+)
+
+// isoMode controls what the ISO lexer will accept
+type isoMode int
+
+const (
+	isoFull    isoMode = iota // a full date with optional time
+	isoReduced                // a date of reduced precision such as 2006 or 2006-01
+	isoTimeOnly               // a time of day with no date such as 15:04 or T1504Z
+)
+
+// isoParts values found by the ISO lexer. Missing parts have been defaulted.
+type isoParts struct {
+	year, month, day           int
+	hour, minute, second, nsec int
+	precision                  int  // section of the least significant part in the input
+	zoneFound                  bool // was there an offset in the input
+	offsetSec                  int  // offset from UTC in seconds
+}
+
+// time make a time from the parts, using location if there was no offset in
+// the input
+func (p isoParts) time(location *time.Location) time.Time {
+	// If no zone was found in scan use default location
+	if p.zoneFound == false {
+		return time.Date(p.year, time.Month(p.month), p.day, p.hour, p.minute, p.second, p.nsec, location)
+	}
+	if p.offsetSec == 0 {
+		return time.Date(p.year, time.Month(p.month), p.day, p.hour, p.minute, p.second, p.nsec, time.UTC)
+	}
+	return time.Date(p.year, time.Month(p.month), p.day, p.hour, p.minute, p.second, p.nsec, LocationFromOffset(p.offsetSec))
+}
+
 // ParseISOTimestamp parse an ISO timetamp iteratively. The reult will be in the
 // zone for the timestamp or if there is no zone offset in the incoming
 // timestamp the incoming location will bue used. It is the responsibility of
 // further steps to standardize to a specific zone offset.
 func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, err error) {
+	parts, err := lexISOTimestamp(timeStr, isoFull)
+	if err != nil {
+		return
+	}
+
+	t = parts.time(location)
+	return
+}
+
+// lexISOTimestamp scan an ISO timestamp into its parts. In isoReduced mode
+// trailing parts may be missing and the precision of the input is reported.
+func lexISOTimestamp(timeStr string, mode isoMode) (parts isoParts, err error) {
 	// Define sections that can change.
 
 	const maxLength int = 35
@@ -383,23 +469,6 @@ func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, er
 	// Needs to not be a const since it gets reassigned
 	var currentSection int = 0 // value for current section
 
-	// Define sections that are constant. Use iota since the incrementing values
-	// correspond to the incremental section processing and give each const a
-	// separate value.
-
-	const (
-		emptySection     int = iota // value for empty section
-		yearSection                 // year - four digits
-		monthSection                // month - 2 digits
-		daySection                  // day - 2 digits
-		hourSection                 // hour - 2 digits
-		minuteSection               // minute - 2 digits
-		secondSection               // second - 2 digits
-		subsecondSection            // subsecond 1-9 digits
-		zoneSection                 // zone +/-HHMM or Z
-		afterSection                // after - when done
-	)
-
 	// Define whether offset is positive for later offset calculation.
 
 	var offsetPositive bool = false // is offset from UTC positive
@@ -466,6 +535,12 @@ func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, er
 			switch currentSection {
 			// Initially no section is active
 			case emptySection:
+				// A time with no date starts with the hour
+				if mode == isoTimeOnly {
+					hourPart, _ = addIf(hourPart, r, hourMax)
+					currentSection = hourSection
+					break
+				}
 				currentSection = yearSection
 				yearPart, partAtMax = addIf(yearPart, r, yearMax)
 				if partAtMax == true {
@@ -539,8 +614,9 @@ func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, er
 			}
 			// currentSection = subsecondSection
 		} else if r == '-' || r == '+' {
-			// Selectively define offset possitivity
-			if currentSection == subsecondSection {
+			// Selectively define offset possitivity. With reduced precision or
+			// a time only an offset can follow hours or minutes.
+			if currentSection == subsecondSection || (mode != isoFull && currentSection > hourSection && currentSection < zoneSection) {
 				offsetPositive = (r == '+')
 				currentSection = zoneSection
 			}
@@ -550,7 +626,8 @@ func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, er
 			// Zulu offset
 		} else if unicode.ToUpper(r) == 'Z' {
 			// define offset as zero for hours and minutes
-			if currentSection == zoneSection || currentSection == subsecondSection {
+			if currentSection == zoneSection || currentSection == subsecondSection ||
+				(mode != isoFull && currentSection > hourSection && currentSection < zoneSection) {
 				zonePart = append(zonePart, '0', '0', '0', '0')
 				break
 			} else {
@@ -628,6 +705,57 @@ func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, er
 	// This will need to be recalculated
 	zoneLen = len(zonePart)
 
+	// Sections are filled in order so the first empty section follows the
+	// least significant part in the input.
+	switch {
+	case subsecondLen > 0:
+		parts.precision = subsecondSection
+	case secondLen > 0:
+		parts.precision = secondSection
+	case minuteLen > 0:
+		parts.precision = minuteSection
+	case hourLen > 0:
+		parts.precision = hourSection
+	case dayLen > 0:
+		parts.precision = daySection
+	case monthLen > 0:
+		parts.precision = monthSection
+	default:
+		parts.precision = yearSection
+	}
+
+	// A time with no date needs an hour and takes a placeholder date of
+	// 0000-01-01 that callers replace.
+	if mode == isoTimeOnly {
+		if hourLen == 0 {
+			err = errors.New("timestamp.ParseISOTimestamp: input has no hour")
+			return
+		}
+		yearPart = append(yearPart, '0', '0', '0', '0')
+		yearLen = yearMax
+	}
+
+	// With reduced precision or a time only default missing parts to the
+	// start of the period the input covers.
+	if mode != isoFull {
+		if monthLen == 0 {
+			monthPart = append(monthPart, '0', '1')
+			monthLen = monthMax
+		}
+		if dayLen == 0 {
+			dayPart = append(dayPart, '0', '1')
+			dayLen = dayMax
+		}
+		if hourLen > 0 && minuteLen == 0 {
+			minutePart = append(minutePart, '0', '0')
+			minuteLen = minuteMax
+		}
+		if minuteLen > 0 && secondLen == 0 {
+			secondPart = append(secondPart, '0', '0')
+			secondLen = secondMax
+		}
+	}
+
 	// Allow for just dates and convert to timestamp with zero valued time parts. Since we are fixing it here it will
 	// pass the next tests if nothing else is wrong or missing.
 	if hourLen == 0 && minuteLen == 0 && secondLen == 0 {
@@ -789,16 +917,15 @@ func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, er
 
 	offsetZero := isZero(zonePart...)
 
-	// Create timestamp based on parts with proper offsset
+	parts.year, parts.month, parts.day = y, m, d
+	parts.hour, parts.minute, parts.second, parts.nsec = h, mn, s, subseconds
+	parts.zoneFound = zoneFound
 
-	// If no zone was found in scan use default location
-	if zoneFound == false {
-		t = time.Date(y, time.Month(m), d, h, mn, s, subseconds, location)
-		return
-	}
+	// Create timestamp based on parts with proper offsset
 
-	if offsetZero == true {
-		t = time.Date(y, time.Month(m), d, h, mn, s, subseconds, time.UTC)
+	// If no zone was found in scan or the offset is zero there is nothing more
+	// to do
+	if zoneFound == false || offsetZero == true {
 		return
 	}
 
@@ -851,6 +978,6 @@ func ParseISOTimestamp(timeStr string, location *time.Location) (t time.Time, er
 		return
 	}
 
-	t = time.Date(y, time.Month(m), d, h, mn, s, subseconds, LocationFromOffset(offsetSec))
+	parts.offsetSec = offsetSec
 	return
 }
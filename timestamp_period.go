@@ -0,0 +1,117 @@
+package timestamp
+
+import (
+	"math"
+	"time"
+
+	"github.com/JohnCGriffin/overflow"
+)
+
+// Period a calendar period made of months, days and an exact clock duration,
+// the way a PostgreSQL interval is stored. The parts are kept apart because
+// months and days vary in length: a month from Jan 31 ends on the last day of
+// February and a day across a daylight saving change keeps the wall clock
+// rather than being 24 hours. The parts may have different signs.
+type Period struct {
+	Months int           // calendar months, with years stored as 12 months
+	Days   int           // calendar days
+	Clock  time.Duration // exact time after the months and days
+}
+
+// IsZero is the period empty
+func (p Period) IsZero() bool {
+	return p.Months == 0 && p.Days == 0 && p.Clock == 0
+}
+
+// Neg get the period with every part negated
+func (p Period) Neg() Period {
+	return Period{Months: -p.Months, Days: -p.Days, Clock: -p.Clock}
+}
+
+// String get the period in ISO-8601 form, such as P1Y2M3DT4H5M6S
+func (p Period) String() string {
+	return FormatPostgresInterval(p, IntervalISO8601)
+}
+
+// AddTo add the period to a time in a location. Months are added first with
+// the day clamped to the end of the month, then days keeping the wall clock,
+// then the clock duration, which is the order PostgreSQL uses. A nil location
+// uses the location of t.
+func (p Period) AddTo(t time.Time, location *time.Location) time.Time {
+	if location != nil {
+		t = t.In(location)
+	}
+	if p.Months != 0 {
+		t = addMonthsClamped(t, p.Months)
+	}
+	if p.Days != 0 {
+		t = t.AddDate(0, 0, p.Days)
+	}
+
+	return t.Add(p.Clock)
+}
+
+// add add months, days and a clock duration to the period. False is returned
+// and the period left alone if any part overflows.
+func (p *Period) add(months, days int64, clock time.Duration) (ok bool) {
+	m, okMonths := overflow.Add64(int64(p.Months), months)
+	d, okDays := overflow.Add64(int64(p.Days), days)
+	c, overflows := DurationOverflows(p.Clock, clock)
+	if !okMonths || !okDays || overflows {
+		return false
+	}
+	p.Months, p.Days, p.Clock = int(m), int(d), time.Duration(c)
+
+	return true
+}
+
+// addUnits add a possibly fractional count of a unit to the period. A
+// fraction of a year goes to whole months, a fraction of a month to days at
+// 30 days a month and a fraction of a day to the clock at 24 hours a day, as
+// PostgreSQL does. Scale multiplies years for decades and centuries.
+func (p *Period) addUnits(negative bool, whole int64, frac float64, unit Unit, scale int64) (ok bool) {
+	var months, days, clock int64
+	var spill float64 // days to split into whole days and clock
+
+	switch unit {
+	case Year, Quarter:
+		per := scale * 12
+		if unit == Quarter {
+			per = 3
+		}
+		if months, ok = overflow.Mul64(whole, per); !ok {
+			return false
+		}
+		months += int64(math.Round(frac * float64(per)))
+	case Month:
+		months = whole
+		spill = frac * 30
+	case Week:
+		if days, ok = overflow.Mul64(whole, 7); !ok {
+			return false
+		}
+		spill = frac * 7
+	case Day:
+		days = whole
+		spill = frac
+	default:
+		length := int64(unit.Duration())
+		if clock, ok = overflow.Mul64(whole, length); !ok {
+			return false
+		}
+		if clock, ok = overflow.Add64(clock, int64(math.Round(frac*float64(length)))); !ok {
+			return false
+		}
+	}
+	if spill != 0 {
+		wholeDays := math.Trunc(spill)
+		days += int64(wholeDays)
+		clock = int64(math.Round((spill - wholeDays) * float64(24*time.Hour)))
+	}
+
+	if negative {
+		months, days, clock = -months, -days, -clock
+	}
+
+	return p.add(months, days, time.Duration(clock))
+}
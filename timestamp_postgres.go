@@ -0,0 +1,508 @@
+package timestamp
+
+import (
+	"strings"
+	"time"
+
+	"github.com/JohnCGriffin/overflow"
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// IntervalStyle one of the PostgreSQL IntervalStyle output formats
+type IntervalStyle int
+
+// PostgreSQL interval styles
+const (
+	IntervalPostgres        IntervalStyle = iota // 1 year 2 mons 3 days 04:05:06.789
+	IntervalPostgresVerbose                      // @ 1 year 2 mons 3 days 4 hours 5 mins 6.789 secs
+	IntervalSQLStandard                          // +1-2 +3 +4:05:06.789
+	IntervalISO8601                              // P1Y2M3DT4H5M6.789S
+)
+
+// intervalScaledUnits interval units that are a number of years
+var intervalScaledUnits = map[string]int64{
+	"decade": 10, "decades": 10, "dec": 10, "decs": 10,
+	"century": 100, "centuries": 100, "cent": 100, "c": 100,
+	"millennium": 1000, "millennia": 1000, "millenniums": 1000, "mil": 1000, "mils": 1000,
+}
+
+// intervalUnit get a unit named in an interval. Names are matched without
+// regard to case as PostgreSQL does, so m is a minute and mon a month.
+func intervalUnit(name string) (unit Unit, scale int64, ok bool) {
+	name = strings.ToLower(name)
+	if scale, ok = intervalScaledUnits[name]; ok {
+		return Year, scale, true
+	}
+	unit, ok = ParseUnit(name)
+
+	return unit, 1, ok
+}
+
+// intervalToken a whitespace separated part of an interval and its position
+type intervalToken struct {
+	text string
+	pos  int
+}
+
+// intervalTokens split an interval on whitespace
+func intervalTokens(value string) (tokens []intervalToken) {
+	start := -1
+	for i := 0; i <= len(value); i++ {
+		space := i == len(value) || value[i] == ' ' || value[i] == '\t' || value[i] == '\n' || value[i] == '\r'
+		switch {
+		case space && start >= 0:
+			tokens = append(tokens, intervalToken{value[start:i], start})
+			start = -1
+		case !space && start < 0:
+			start = i
+		}
+	}
+
+	return
+}
+
+// scanIntervalNumber read an unsigned number with an optional fraction from
+// the start of s. A comma may be used as the decimal point as ISO-8601 allows.
+// The length read is 0 if there is no number.
+func scanIntervalNumber(s string) (whole int64, frac float64, length int, ok bool) {
+	ok = true
+	digits := 0
+	for length < len(s) && s[length] >= '0' && s[length] <= '9' {
+		if ok {
+			if whole, ok = overflow.Mul64(whole, 10); ok {
+				whole, ok = overflow.Add64(whole, int64(s[length]-'0'))
+			}
+		}
+		length++
+		digits++
+	}
+	if length < len(s) && (s[length] == '.' || s[length] == ',') {
+		length++
+		scale := 1.0
+		for length < len(s) && s[length] >= '0' && s[length] <= '9' {
+			scale /= 10
+			frac += float64(s[length]-'0') * scale
+			length++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0, 0, 0, true
+	}
+
+	return
+}
+
+// isYearMonth is the text the SQL standard years-months form, such as 1-2
+func isYearMonth(text string) bool {
+	i := strings.IndexByte(text, '-')
+	return i > 0 && isDigits(text[:i]) && isDigits(text[i+1:])
+}
+
+// ParsePostgresInterval parse an interval written in any of the PostgreSQL
+// IntervalStyle formats:
+//
+//	1 year 2 mons 3 days 04:05:06.789   postgres
+//	-1 days +02:00:00                   postgres
+//	@ 1 hour ago                        postgres_verbose
+//	1-2, 3 4:05:06, +1-2 -3 +4:05:06    sql_standard
+//	P1Y2M3DT4H5M6.789S                  iso_8601
+//
+// A number without a unit is seconds, or days when it comes before a time as
+// in 3 04:05:06. Fractions of years, months and days spill into smaller
+// parts as they do in PostgreSQL, so 1.5 months is 1 month and 15 days.
+//
+// The style is used for the one input that PostgreSQL reads differently by
+// style. With IntervalSQLStandard a leading minus sign applies to every field
+// when no other field has a sign, so -1 2:00:00 is minus a day and two hours
+// rather than minus a day plus two hours.
+func ParsePostgresInterval(value string, style IntervalStyle) (p Period, err error) {
+	const caller = "timestamp.ParsePostgresInterval"
+
+	tokens := intervalTokens(value)
+	if len(tokens) == 0 {
+		return p, durationError(caller, "empty interval", value, -1)
+	}
+	if len(tokens) == 1 && tokens[0].text[0] == 'P' {
+		return parseISOInterval(caller, value, tokens[0].pos)
+	}
+
+	// The postgres_verbose form starts with @ and may end with ago
+	if tokens[0].text == "@" {
+		tokens = tokens[1:]
+	} else if tokens[0].text[0] == '@' {
+		tokens[0].text, tokens[0].pos = tokens[0].text[1:], tokens[0].pos+1
+	}
+	ago := false
+	if len(tokens) > 0 && strings.EqualFold(tokens[len(tokens)-1].text, "ago") {
+		ago = true
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return p, durationError(caller, "no interval found", value, -1)
+	}
+
+	negateAll := style == IntervalSQLStandard && tokens[0].text[0] == '-'
+	for _, tok := range tokens[1:] {
+		if tok.text[0] == '-' || tok.text[0] == '+' {
+			negateAll = false
+		}
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		text, pos := tokens[i].text, tokens[i].pos
+		negative := negateAll
+		if text[0] == '-' || text[0] == '+' {
+			negative = text[0] == '-'
+			text, pos = text[1:], pos+1
+		}
+
+		switch {
+		case strings.IndexByte(text, ':') >= 0:
+			clock, err := parseClockDuration(caller, text, value, pos, negative)
+			if err != nil {
+				return Period{}, err
+			}
+			if !p.add(0, 0, clock) {
+				return Period{}, durationError(caller, "interval too large", value, pos)
+			}
+			continue
+		case isYearMonth(text):
+			dash := strings.IndexByte(text, '-')
+			years, _ := atoiDigits(text[:dash])
+			months, _ := atoiDigits(text[dash+1:])
+			if months > 11 {
+				return Period{}, durationError(caller, "months must be 0 to 11", value, pos+dash+1)
+			}
+			total, ok := overflow.Mul64(int64(years), 12)
+			if ok {
+				total, ok = overflow.Add64(total, int64(months))
+			}
+			if negative {
+				total = -total
+			}
+			if !ok || !p.add(total, 0, 0) {
+				return Period{}, durationError(caller, "interval too large", value, pos)
+			}
+			continue
+		}
+
+		whole, frac, length, ok := scanIntervalNumber(text)
+		if length == 0 {
+			return Period{}, durationError(caller, "expected a number", value, pos)
+		}
+		if !ok {
+			return Period{}, durationError(caller, "number too large", value, pos)
+		}
+
+		// The unit is attached, in the next token or implied
+		name, namePos := text[length:], pos+length
+		unit, scale := Second, int64(1)
+		switch {
+		case name != "":
+		case i+1 < len(tokens) && isLetter(tokens[i+1].text[0]):
+			i++
+			name, namePos = tokens[i].text, tokens[i].pos
+		case i+1 < len(tokens) && strings.IndexByte(tokens[i+1].text, ':') >= 0:
+			unit = Day
+		}
+		if name != "" {
+			if unit, scale, ok = intervalUnit(name); !ok {
+				return Period{}, durationError(caller, "unknown interval unit", value, namePos)
+			}
+		}
+		if !p.addUnits(negative, whole, frac, unit, scale) {
+			return Period{}, durationError(caller, "interval too large", value, pos)
+		}
+	}
+
+	if ago {
+		p = p.Neg()
+	}
+
+	return p, nil
+}
+
+// isLetter is the byte an ASCII letter or the start of a multi-byte character
+// such as the µ in µs
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// parseISOInterval parse the ISO-8601 designator form of an interval, such as
+// P1Y2M3DT4H5M6S or P-1DT-2H, starting at pos in value
+func parseISOInterval(caller string, value string, pos int) (p Period, err error) {
+	s := value[pos:]
+	if i := strings.IndexAny(s, " \t\n\r"); i >= 0 {
+		s = s[:i]
+	}
+
+	inTime, found := false, false
+	for i := 1; i < len(s); {
+		if s[i] == 'T' {
+			if inTime {
+				return Period{}, durationError(caller, "repeated T", value, pos+i)
+			}
+			inTime = true
+			i++
+			continue
+		}
+
+		start := i
+		negative := false
+		if s[i] == '-' || s[i] == '+' {
+			negative = s[i] == '-'
+			i++
+		}
+		whole, frac, length, ok := scanIntervalNumber(s[i:])
+		if length == 0 {
+			return Period{}, durationError(caller, "expected a number", value, pos+i)
+		}
+		if !ok {
+			return Period{}, durationError(caller, "number too large", value, pos+start)
+		}
+		i += length
+		if i == len(s) {
+			return Period{}, durationError(caller, "missing designator", value, pos+start)
+		}
+
+		var unit Unit
+		switch {
+		case !inTime && s[i] == 'Y':
+			unit = Year
+		case !inTime && s[i] == 'M':
+			unit = Month
+		case !inTime && s[i] == 'W':
+			unit = Week
+		case !inTime && s[i] == 'D':
+			unit = Day
+		case inTime && s[i] == 'H':
+			unit = Hour
+		case inTime && s[i] == 'M':
+			unit = Minute
+		case inTime && s[i] == 'S':
+			unit = Second
+		default:
+			return Period{}, durationError(caller, "unknown designator", value, pos+i)
+		}
+		if !p.addUnits(negative, whole, frac, unit, 1) {
+			return Period{}, durationError(caller, "interval too large", value, pos+start)
+		}
+		found = true
+		i++
+	}
+	if !found {
+		return Period{}, durationError(caller, "no interval found", value, pos)
+	}
+
+	return p, nil
+}
+
+// FormatPostgresInterval write a period the way PostgreSQL writes an interval
+// in an IntervalStyle. Years are whole multiples of 12 months. Fractions of a
+// second are written to the nanosecond with trailing zeros removed, where
+// PostgreSQL stops at the microsecond.
+func FormatPostgresInterval(p Period, style IntervalStyle) string {
+	xfmtBuf := make(xfmt.Buffer, 0, 32)
+	xfmtBuf = appendPostgresInterval(xfmtBuf, p, style)
+
+	return string(xfmtBuf.Bytes())
+}
+
+// abs get the absolute value of an int
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// appendIntervalSeconds append seconds and a trimmed fraction without a sign
+func appendIntervalSeconds(b []byte, sec, fsec int, fill bool) []byte {
+	if fill {
+		b = appendInt(b, abs(sec), 2, '0')
+	} else {
+		b = appendInt(b, abs(sec), 0, 0)
+	}
+
+	return appendFraction(b, abs(fsec), 9, true, ".")
+}
+
+// appendPostgresInterval append a period in an interval style. This follows
+// EncodeInterval in PostgreSQL's datetime.c.
+func appendPostgresInterval(b []byte, p Period, style IntervalStyle) []byte {
+	year, mon, mday := p.Months/12, p.Months%12, p.Days
+	hour, min := int(p.Clock/time.Hour), int(p.Clock/time.Minute%60)
+	sec, fsec := int(p.Clock/time.Second%60), int(p.Clock%time.Second)
+
+	switch style {
+	case IntervalPostgresVerbose:
+		b = append(b, '@')
+		isZero, isBefore := true, false
+		part := func(v int, unit string) {
+			if v == 0 {
+				return
+			}
+			// The first part sets the sign and later parts are relative to it
+			if isZero {
+				isBefore = v < 0
+				v = abs(v)
+			} else if isBefore {
+				v = -v
+			}
+			b = append(b, ' ')
+			b = appendInt(b, v, 0, 0)
+			b = append(b, ' ')
+			b = append(b, unit...)
+			if v != 1 {
+				b = append(b, 's')
+			}
+			isZero = false
+		}
+		part(year, "year")
+		part(mon, "mon")
+		part(mday, "day")
+		part(hour, "hour")
+		part(min, "min")
+		if sec != 0 || fsec != 0 {
+			b = append(b, ' ')
+			if sec < 0 || (sec == 0 && fsec < 0) {
+				if isZero {
+					isBefore = true
+				} else if !isBefore {
+					b = append(b, '-')
+				}
+			} else if isBefore {
+				b = append(b, '-')
+			}
+			b = appendIntervalSeconds(b, sec, fsec, false)
+			b = append(b, " sec"...)
+			if abs(sec) != 1 || fsec != 0 {
+				b = append(b, 's')
+			}
+			isZero = false
+		}
+		if isZero {
+			b = append(b, " 0"...)
+		}
+		if isBefore {
+			b = append(b, " ago"...)
+		}
+		return b
+
+	case IntervalSQLStandard:
+		hasNegative := year < 0 || mon < 0 || mday < 0 || p.Clock < 0
+		hasPositive := year > 0 || mon > 0 || mday > 0 || p.Clock > 0
+		hasYearMonth := year != 0 || mon != 0
+		hasDayTime := mday != 0 || p.Clock != 0
+		standard := !(hasNegative && hasPositive) && !(hasYearMonth && hasDayTime)
+
+		// A standard value has one sign in front of everything
+		if hasNegative && standard {
+			b = append(b, '-')
+			year, mon, mday, hour, min, sec, fsec = -year, -mon, -mday, -hour, -min, -sec, -fsec
+		}
+		switch {
+		case !hasNegative && !hasPositive:
+			return append(b, '0')
+		case !standard:
+			// Mixed values have a sign on each part
+			sign := func(negative bool) {
+				if negative {
+					b = append(b, '-')
+				} else {
+					b = append(b, '+')
+				}
+			}
+			sign(year < 0 || mon < 0)
+			b = appendInt(b, abs(year), 0, 0)
+			b = append(b, '-')
+			b = appendInt(b, abs(mon), 0, 0)
+			b = append(b, ' ')
+			sign(mday < 0)
+			b = appendInt(b, abs(mday), 0, 0)
+			b = append(b, ' ')
+			sign(p.Clock < 0)
+		case hasYearMonth:
+			b = appendInt(b, year, 0, 0)
+			b = append(b, '-')
+			return appendInt(b, mon, 0, 0)
+		case mday != 0:
+			b = appendInt(b, mday, 0, 0)
+			b = append(b, ' ')
+		}
+		b = appendInt(b, abs(hour), 0, 0)
+		b = append(b, ':')
+		b = appendInt(b, abs(min), 2, '0')
+		b = append(b, ':')
+		return appendIntervalSeconds(b, sec, fsec, true)
+
+	case IntervalISO8601:
+		if p.IsZero() {
+			return append(b, "PT0S"...)
+		}
+		part := func(v int, designator byte) {
+			if v != 0 {
+				b = appendInt(b, v, 0, 0)
+				b = append(b, designator)
+			}
+		}
+		b = append(b, 'P')
+		part(year, 'Y')
+		part(mon, 'M')
+		part(mday, 'D')
+		if p.Clock != 0 {
+			b = append(b, 'T')
+		}
+		part(hour, 'H')
+		part(min, 'M')
+		if sec != 0 || fsec != 0 {
+			if p.Clock < 0 {
+				b = append(b, '-')
+			}
+			b = appendIntervalSeconds(b, sec, fsec, false)
+			b = append(b, 'S')
+		}
+		return b
+	}
+
+	isZero, isBefore := true, false
+	part := func(v int, unit string) {
+		if v == 0 {
+			return
+		}
+		if !isZero {
+			b = append(b, ' ')
+		}
+		if isBefore && v > 0 {
+			b = append(b, '+')
+		}
+		b = appendInt(b, v, 0, 0)
+		b = append(b, ' ')
+		b = append(b, unit...)
+		if v != 1 {
+			b = append(b, 's')
+		}
+		isBefore, isZero = v < 0, false
+	}
+	part(year, "year")
+	part(mon, "mon")
+	part(mday, "day")
+	if isZero || p.Clock != 0 {
+		if !isZero {
+			b = append(b, ' ')
+		}
+		if p.Clock < 0 {
+			b = append(b, '-')
+		} else if isBefore {
+			b = append(b, '+')
+		}
+		b = appendInt(b, abs(hour), 2, '0')
+		b = append(b, ':')
+		b = appendInt(b, abs(min), 2, '0')
+		b = append(b, ':')
+		b = appendIntervalSeconds(b, sec, fsec, true)
+	}
+
+	return b
+}
@@ -0,0 +1,116 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestParsePostgresInterval(t *testing.T) {
+	is := is.New(t)
+
+	clock := 4*time.Hour + 5*time.Minute + 6789*time.Millisecond
+
+	tests := []struct {
+		input    string
+		style    timestamp.IntervalStyle
+		expected timestamp.Period
+	}{
+		{"1 year 2 mons 3 days 04:05:06.789", timestamp.IntervalPostgres, timestamp.Period{Months: 14, Days: 3, Clock: clock}},
+		{"-1 days +02:00:00", timestamp.IntervalPostgres, timestamp.Period{Days: -1, Clock: 2 * time.Hour}},
+		{"00:00:00", timestamp.IntervalPostgres, timestamp.Period{}},
+		{"@ 1 hour ago", timestamp.IntervalPostgresVerbose, timestamp.Period{Clock: -time.Hour}},
+		{"@ 1 year 2 mons 3 days 4 hours 5 mins 6.789 secs", timestamp.IntervalPostgresVerbose, timestamp.Period{Months: 14, Days: 3, Clock: clock}},
+		{"@ 1 day -2 hours ago", timestamp.IntervalPostgresVerbose, timestamp.Period{Days: -1, Clock: 2 * time.Hour}},
+		{"1-2", timestamp.IntervalSQLStandard, timestamp.Period{Months: 14}},
+		{"3 4:05:06.789", timestamp.IntervalSQLStandard, timestamp.Period{Days: 3, Clock: clock}},
+		{"-3 4:05:06.789", timestamp.IntervalSQLStandard, timestamp.Period{Days: -3, Clock: -clock}},
+		{"-3 4:05:06.789", timestamp.IntervalPostgres, timestamp.Period{Days: -3, Clock: clock}},
+		{"+1-2 -3 +4:05:06.789", timestamp.IntervalSQLStandard, timestamp.Period{Months: 14, Days: -3, Clock: clock}},
+		{"P1Y2M3DT4H5M6.789S", timestamp.IntervalISO8601, timestamp.Period{Months: 14, Days: 3, Clock: clock}},
+		{"P-1DT-2H", timestamp.IntervalISO8601, timestamp.Period{Days: -1, Clock: -2 * time.Hour}},
+		{"P2W", timestamp.IntervalISO8601, timestamp.Period{Days: 14}},
+		{"1.5 mons", timestamp.IntervalPostgres, timestamp.Period{Months: 1, Days: 15}},
+		{"1.5 days", timestamp.IntervalPostgres, timestamp.Period{Days: 1, Clock: 12 * time.Hour}},
+		{"1 decade 2y", timestamp.IntervalPostgres, timestamp.Period{Months: 144}},
+		{"90", timestamp.IntervalPostgres, timestamp.Period{Clock: 90 * time.Second}},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.ParsePostgresInterval(test.input, test.style)
+		is.NoErr(err)                // Should parse without error
+		is.Equal(got, test.expected) // Period should match
+		t.Logf("%q got %+v", test.input, got)
+	}
+
+	for _, input := range []string{"", "@", "1 fortnight", "1-12", "P", "P1H", "PT1D", "1:60:00", "abc", "9999999999999999999 years"} {
+		_, err := timestamp.ParsePostgresInterval(input, timestamp.IntervalPostgres)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+}
+
+func TestFormatPostgresInterval(t *testing.T) {
+	is := is.New(t)
+
+	full := timestamp.Period{Months: 14, Days: 3, Clock: 4*time.Hour + 5*time.Minute + 6789*time.Millisecond}
+	mixed := timestamp.Period{Days: -1, Clock: 2 * time.Hour}
+	hourAgo := timestamp.Period{Clock: -time.Hour}
+
+	tests := []struct {
+		p        timestamp.Period
+		style    timestamp.IntervalStyle
+		expected string
+	}{
+		{full, timestamp.IntervalPostgres, "1 year 2 mons 3 days 04:05:06.789"},
+		{mixed, timestamp.IntervalPostgres, "-1 days +02:00:00"},
+		{timestamp.Period{}, timestamp.IntervalPostgres, "00:00:00"},
+		{full, timestamp.IntervalPostgresVerbose, "@ 1 year 2 mons 3 days 4 hours 5 mins 6.789 secs"},
+		{hourAgo, timestamp.IntervalPostgresVerbose, "@ 1 hour ago"},
+		{mixed, timestamp.IntervalPostgresVerbose, "@ 1 day -2 hours ago"},
+		{timestamp.Period{}, timestamp.IntervalPostgresVerbose, "@ 0"},
+		{full, timestamp.IntervalSQLStandard, "+1-2 +3 +4:05:06.789"},
+		{timestamp.Period{Months: -14}, timestamp.IntervalSQLStandard, "-1-2"},
+		{timestamp.Period{Days: -3, Clock: -time.Hour}, timestamp.IntervalSQLStandard, "-3 1:00:00"},
+		{mixed, timestamp.IntervalSQLStandard, "+0-0 -1 +2:00:00"},
+		{timestamp.Period{}, timestamp.IntervalSQLStandard, "0"},
+		{full, timestamp.IntervalISO8601, "P1Y2M3DT4H5M6.789S"},
+		{mixed, timestamp.IntervalISO8601, "P-1DT2H"},
+		{timestamp.Period{Clock: -90 * time.Second}, timestamp.IntervalISO8601, "PT-1M-30S"},
+		{timestamp.Period{}, timestamp.IntervalISO8601, "PT0S"},
+	}
+
+	for _, test := range tests {
+		got := timestamp.FormatPostgresInterval(test.p, test.style)
+		is.Equal(got, test.expected) // Output should match
+		t.Logf("%+v got %q", test.p, got)
+
+		back, err := timestamp.ParsePostgresInterval(got, test.style)
+		is.NoErr(err)          // Output should parse
+		is.Equal(back, test.p) // Output should round trip
+	}
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	// Months clamp to the end of the month
+	start := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	got := timestamp.Period{Months: 1}.AddTo(start, nil)
+	is.Equal(got, time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC))
+
+	// A day across the spring change keeps the wall clock and is 23 hours
+	start = time.Date(2024, 3, 9, 12, 0, 0, 0, toronto)
+	got = timestamp.Period{Days: 1}.AddTo(start.UTC(), toronto)
+	is.Equal(got.Hour(), 12)               // Wall clock should be kept
+	is.Equal(got.Sub(start), 23*time.Hour) // Day should be 23 hours
+
+	// The clock part is exact
+	got = timestamp.Period{Clock: 24 * time.Hour}.AddTo(start, toronto)
+	is.Equal(got.Hour(), 13) // Clock part should be exact
+}
@@ -0,0 +1,225 @@
+package timestamp
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// precisionUnits the unit covered by an ISO input of a given precision
+var precisionUnits = map[int]Unit{
+	yearSection:      Year,
+	monthSection:     Month,
+	daySection:       Day,
+	hourSection:      Hour,
+	minuteSection:    Minute,
+	secondSection:    Second,
+	subsecondSection: Nanosecond,
+}
+
+// rangeError make an error for a range expression
+func rangeError(reason string, part string, expr string) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.ParseRange: ").S(reason)
+	if part != "" {
+		xfmtBuf.S(" '").S(part).S("'")
+	}
+	xfmtBuf.S(" in input ").S(expr)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// ParseRange parse a time range expression into a half-open [start, end)
+// window. The reference time is used for expressions relative to now and
+// location is used for calendar spans and for timestamps with no zone.
+//
+// Supported forms:
+//
+//	2024-01-05T10:00Z..2024-01-05T12:00Z   between two instants
+//	2024-01..2024-03                       January through the end of March
+//	2024, 2024-01, 2024-01-05              a whole year, month or day
+//	today, yesterday, tomorrow             a whole day
+//	this week, last month, next year       a whole calendar unit
+//	last 7d, last 2 hours, next 3 days     a window ending or starting at ref
+//
+// Endpoints of an a..b range that are a date of reduced precision (a year,
+// month or day) expand to their whole calendar span, so the start is the
+// first instant of a and the end is the first instant after b. Day spans
+// start at the first instant of the day, which is after midnight when a
+// daylight saving change skips it. Endpoints that
+// include a time of day are taken as instants. Other endpoints are evaluated
+// with ParseRelative.
+//
+// A single ISO timestamp spans the unit of its precision, so 2024-01-05T10
+// is the hour starting at 10:00.
+func ParseRange(expr string, ref time.Time, location *time.Location) (start, end time.Time, err error) {
+	if location == nil {
+		location = ref.Location()
+	}
+	ref = ref.In(location)
+
+	trimmed := strings.TrimSpace(expr)
+	if trimmed == "" {
+		err = rangeError("empty expression", "", expr)
+		return
+	}
+
+	if i := strings.Index(trimmed, ".."); i >= 0 {
+		left, right := strings.TrimSpace(trimmed[:i]), strings.TrimSpace(trimmed[i+2:])
+		if left == "" || right == "" {
+			err = rangeError("both ends of a range are required", "", expr)
+			return
+		}
+		var spanEnd time.Time
+		if start, _, err = rangeEndpoint(left, ref, location, expr); err != nil {
+			return
+		}
+		if _, spanEnd, err = rangeEndpoint(right, ref, location, expr); err != nil {
+			return
+		}
+		end = spanEnd
+		if end.Before(start) {
+			err = rangeError("end is before start", "", expr)
+		}
+		return
+	}
+
+	if start, end, ok := rangeWindow(trimmed, ref); ok {
+		return start, end, nil
+	}
+
+	if start, end, ok := rangeDay(trimmed, ref); ok {
+		return start, end, nil
+	}
+
+	parts, lexErr := lexISOTimestamp(trimmed, isoReduced)
+	if lexErr != nil {
+		err = rangeError("could not parse", trimmed, expr)
+		return
+	}
+	start, end = rangeSpan(parts, location)
+
+	return
+}
+
+// rangeEndpoint get the span for one end of an a..b range. Instants have an
+// empty span with start and end equal.
+func rangeEndpoint(text string, ref time.Time, location *time.Location, expr string) (start, end time.Time, err error) {
+	if start, end, ok := rangeDay(text, ref); ok {
+		return start, end, nil
+	}
+
+	parts, lexErr := lexISOTimestamp(text, isoReduced)
+	if lexErr == nil {
+		if parts.precision <= daySection {
+			start, end = rangeSpan(parts, location)
+			return
+		}
+		start = parts.time(location)
+		return start, start, nil
+	}
+
+	start, err = ParseRelative(text, ref, location)
+	if err != nil {
+		err = rangeError("could not parse endpoint", text, expr)
+		return
+	}
+	end = start
+
+	return
+}
+
+// rangeSpan get the span of the unit of an ISO input's precision. Dates start
+// at the first instant of the day, which is after midnight when a daylight
+// saving change skips it.
+func rangeSpan(parts isoParts, location *time.Location) (start, end time.Time) {
+	start = parts.time(location)
+	unit := precisionUnits[parts.precision]
+	if parts.precision <= daySection {
+		start = firstInstant(Date{Year: parts.year, Month: time.Month(parts.month), Day: parts.day}, start.Location())
+	}
+
+	return start, nextStartOfUnit(start, unit)
+}
+
+// rangeDay handle today, yesterday and tomorrow as whole days
+func rangeDay(text string, ref time.Time) (start, end time.Time, ok bool) {
+	days := 0
+	switch strings.ToLower(text) {
+	case "today":
+	case "yesterday":
+		days = -1
+	case "tomorrow":
+		days = 1
+	default:
+		return
+	}
+	start = firstInstant(DateOf(ref).AddDays(days), ref.Location())
+
+	return start, nextStartOfUnit(start, Day), true
+}
+
+// rangeWindow handle "this unit", "last unit", "next unit" and the trailing
+// and leading windows "last N unit" and "next N unit"
+func rangeWindow(text string, ref time.Time) (start, end time.Time, ok bool) {
+	fields := strings.Fields(strings.ToLower(text))
+	if len(fields) < 2 || len(fields) > 3 {
+		return
+	}
+	word := fields[0]
+	if word != "this" && word != "last" && word != "next" && word != "past" {
+		return
+	}
+
+	// Unit names are looked up in the original case so "M" and "m" differ
+	original := strings.Fields(text)
+
+	// A whole calendar unit such as "last month"
+	if len(fields) == 2 && word != "past" {
+		if unit, found := ParseUnit(original[1]); found && unit >= Minute {
+			start = startOfUnit(ref, unit)
+			switch word {
+			case "last":
+				start = shiftStartOfUnit(start, -1, unit)
+			case "next":
+				start = shiftStartOfUnit(start, 1, unit)
+			}
+			return start, nextStartOfUnit(start, unit), true
+		}
+	}
+	if word == "this" {
+		return
+	}
+
+	// A window with a count such as "last 7d" or "next 3 days"
+	countText, unitText := original[1], ""
+	if len(fields) == 3 {
+		unitText = original[2]
+	} else {
+		i := 0
+		for i < len(countText) && countText[i] >= '0' && countText[i] <= '9' {
+			i++
+		}
+		countText, unitText = countText[:i], countText[i:]
+	}
+	n, convErr := strconv.Atoi(countText)
+	if convErr != nil || n < 0 {
+		return
+	}
+	unit, found := ParseUnit(unitText)
+	if !found {
+		return
+	}
+	if _, fits := unitsDuration(n, unit); !fits && !unit.IsCalendar() {
+		return
+	}
+	if word == "next" {
+		return ref, addUnits(ref, n, unit), true
+	}
+
+	return addUnits(ref, -n, unit), ref, true
+}
@@ -0,0 +1,94 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestParseRange(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err) // Location should load
+
+	ref := time.Date(2024, 3, 13, 14, 25, 0, 0, toronto)
+	date := func(y int, m time.Month, d, h, mn int) time.Time {
+		return time.Date(y, m, d, h, mn, 0, 0, toronto)
+	}
+
+	tests := []struct {
+		expr       string
+		start, end time.Time
+	}{
+		{"2024-01-05T10:00Z..2024-01-05T12:00Z",
+			time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC), time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)},
+		{"2024-01..2024-03", date(2024, 1, 1, 0, 0), date(2024, 4, 1, 0, 0)},
+		{"2023..2024-02-10", date(2023, 1, 1, 0, 0), date(2024, 2, 11, 0, 0)},
+		{"yesterday..now", date(2024, 3, 12, 0, 0), ref},
+		{"2024", date(2024, 1, 1, 0, 0), date(2025, 1, 1, 0, 0)},
+		{"2024-02", date(2024, 2, 1, 0, 0), date(2024, 3, 1, 0, 0)},
+		{"2024-03-10", date(2024, 3, 10, 0, 0), date(2024, 3, 11, 0, 0)},
+		{"2024-03-10T10", date(2024, 3, 10, 10, 0), date(2024, 3, 10, 11, 0)},
+		{"today", date(2024, 3, 13, 0, 0), date(2024, 3, 14, 0, 0)},
+		{"yesterday", date(2024, 3, 12, 0, 0), date(2024, 3, 13, 0, 0)},
+		{"this week", date(2024, 3, 11, 0, 0), date(2024, 3, 18, 0, 0)},
+		{"last month", date(2024, 2, 1, 0, 0), date(2024, 3, 1, 0, 0)},
+		{"next year", date(2025, 1, 1, 0, 0), date(2026, 1, 1, 0, 0)},
+		{"last 7d", date(2024, 3, 6, 14, 25), ref},
+		{"last 2 hours", date(2024, 3, 13, 12, 25), ref},
+		{"next 3 days", ref, date(2024, 3, 16, 14, 25)},
+	}
+
+	for _, test := range tests {
+		start, end, err := timestamp.ParseRange(test.expr, ref, toronto)
+		is.NoErr(err) // Range should parse
+		t.Logf("expr %q start %v end %v", test.expr, start, end)
+		is.True(start.Equal(test.start)) // Start should match
+		is.True(end.Equal(test.end))     // End should match
+	}
+
+	// The day of the spring change is 23 hours long
+	start, end, err := timestamp.ParseRange("2024-03-10", ref, toronto)
+	is.NoErr(err)
+	is.Equal(end.Sub(start), 23*time.Hour)
+
+	for _, expr := range []string{"", "2024-03..", "2024-03..2024-01", "sometime", "2024-1", "last 3000000 hours", "next 200000000000s"} {
+		_, _, err = timestamp.ParseRange(expr, ref, toronto)
+		t.Logf("expr %q error %v", expr, err)
+		is.True(err != nil) // Should be an error
+	}
+}
+
+func TestParseRangeSkippedMidnight(t *testing.T) {
+	is := is.New(t)
+
+	// Midnight is skipped in Santiago on 2022-09-11, which starts at 01:00
+	santiago, err := time.LoadLocation("America/Santiago")
+	is.NoErr(err)
+
+	tests := []struct {
+		expr  string
+		ref   time.Time
+		start string
+		end   string
+	}{
+		{"today", time.Date(2022, 9, 11, 12, 0, 0, 0, santiago), "2022-09-11T01:00:00-03:00", "2022-09-12T00:00:00-03:00"},
+		{"yesterday", time.Date(2022, 9, 11, 12, 0, 0, 0, santiago), "2022-09-10T00:00:00-04:00", "2022-09-11T01:00:00-03:00"},
+		{"tomorrow", time.Date(2022, 9, 10, 12, 0, 0, 0, santiago), "2022-09-11T01:00:00-03:00", "2022-09-12T00:00:00-03:00"},
+		{"this day", time.Date(2022, 9, 11, 12, 0, 0, 0, santiago), "2022-09-11T01:00:00-03:00", "2022-09-12T00:00:00-03:00"},
+		{"next day", time.Date(2022, 9, 10, 12, 0, 0, 0, santiago), "2022-09-11T01:00:00-03:00", "2022-09-12T00:00:00-03:00"},
+		{"last week", time.Date(2022, 9, 14, 12, 0, 0, 0, santiago), "2022-09-05T00:00:00-04:00", "2022-09-12T00:00:00-03:00"},
+		{"2022-09-11", time.Date(2022, 9, 14, 12, 0, 0, 0, santiago), "2022-09-11T01:00:00-03:00", "2022-09-12T00:00:00-03:00"},
+		{"2022-09-10..2022-09-11", time.Date(2022, 9, 14, 12, 0, 0, 0, santiago), "2022-09-10T00:00:00-04:00", "2022-09-12T00:00:00-03:00"},
+	}
+
+	for _, test := range tests {
+		start, end, err := timestamp.ParseRange(test.expr, test.ref, santiago)
+		is.NoErr(err)                                    // Range should parse
+		is.Equal(start.Format(time.RFC3339), test.start) // Start should match
+		is.Equal(end.Format(time.RFC3339), test.end)     // End should match
+	}
+}
@@ -0,0 +1,542 @@
+package timestamp
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// relativeToken a word from a relative expression along with its byte
+// position in the original input, used for error reporting.
+type relativeToken struct {
+	text string
+	pos  int
+}
+
+// tokenizeRelative split a relative expression into words. Whitespace and
+// commas separate words.
+func tokenizeRelative(expr string) (tokens []relativeToken) {
+	start := -1
+	for i, r := range expr {
+		if unicode.IsSpace(r) || r == ',' {
+			if start >= 0 {
+				tokens = append(tokens, relativeToken{text: expr[start:i], pos: start})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, relativeToken{text: expr[start:], pos: start})
+	}
+
+	return
+}
+
+var relativeWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// relativeOffset an amount of a unit to add once all anchors are applied
+type relativeOffset struct {
+	n    int
+	unit Unit
+}
+
+// relativeParser state used while evaluating a relative expression
+type relativeParser struct {
+	input  string
+	tokens []relativeToken
+	i      int
+
+	t       time.Time        // current anchor in the target location
+	offsets []relativeOffset // offsets applied after anchors
+	pending int              // index of first offset "ago" would apply to
+
+	clockSet             bool
+	hour, min, sec, nsec int
+}
+
+// ParseRelative parse a relative or natural language time expression and
+// evaluate it against a reference time in location. This is a separate entry
+// point from ParseInLocation; the strict parsers never interpret relative
+// expressions.
+//
+// Supported forms, which may be combined:
+//
+//	now, today, yesterday, tomorrow
+//	noon, midnight, 09:00, 09:00:30.5, 9am, 9:30pm
+//	3 days ago, 2 hours 30 minutes ago, in 2 hours, +1d, -90m, an hour ago
+//	next monday, last friday, this sunday, monday
+//	next week, last month, this year
+//	first day of next month, last day of this year
+//	2024-01-05 (an ISO date or timestamp as the anchor)
+//
+// Named days and weekdays reset the clock to midnight. Offsets keep the wall
+// clock. Offsets in days, weeks, months, quarters and years are applied to
+// the calendar date in location so a day is not assumed to be 24 hours across
+// a daylight saving change. Month steps are clamped to the end of the month.
+// Offsets in hours and smaller are exact durations and an offset too large
+// for a time.Duration is an error.
+//
+// A clock time is applied after anchors and before offsets so "3 days ago
+// 09:00" is 09:00 three days before the reference.
+//
+// On failure the error reports the token that could not be used and its
+// position in the input.
+func ParseRelative(expr string, ref time.Time, location *time.Location) (t time.Time, err error) {
+	if location == nil {
+		location = ref.Location()
+	}
+	p := relativeParser{
+		input:  expr,
+		tokens: tokenizeRelative(expr),
+		t:      ref.In(location),
+	}
+	if len(p.tokens) == 0 {
+		err = errors.New("timestamp.ParseRelative: empty expression")
+		return
+	}
+
+	for p.i < len(p.tokens) {
+		if err = p.clause(); err != nil {
+			return
+		}
+	}
+
+	return p.result(), nil
+}
+
+// result apply the clock and offsets to the anchor
+func (p *relativeParser) result() time.Time {
+	t := p.t
+	if p.clockSet {
+		y, m, d := t.Date()
+		t = time.Date(y, m, d, p.hour, p.min, p.sec, p.nsec, t.Location())
+	}
+	// Calendar offsets first so that exact offsets are counted from the
+	// resulting wall clock time.
+	for _, o := range p.offsets {
+		if o.unit.IsCalendar() {
+			t = addUnits(t, o.n, o.unit)
+		}
+	}
+	for _, o := range p.offsets {
+		if !o.unit.IsCalendar() {
+			t = addUnits(t, o.n, o.unit)
+		}
+	}
+
+	return t
+}
+
+// errorAt make an error pointing at a token
+func (p *relativeParser) errorAt(tok relativeToken, reason string) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.ParseRelative: ").S(reason).S(" '").S(tok.text).S("'").C('@').D(tok.pos).S(" in input ").S(p.input)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// errorAtEnd make an error for an expression that ended too early
+func (p *relativeParser) errorAtEnd(reason string) error {
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.ParseRelative: ").S(reason).S(" at end of input ").S(p.input)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// peek get the lower case text of the token at offset from the current
+// position or an empty string past the end
+func (p *relativeParser) peek(offset int) string {
+	if p.i+offset >= len(p.tokens) {
+		return ""
+	}
+	return strings.ToLower(p.tokens[p.i+offset].text)
+}
+
+// midnight set the anchor to the start of its day plus a number of days
+func (p *relativeParser) midnight(days int) {
+	y, m, d := p.t.Date()
+	p.t = time.Date(y, m, d+days, 0, 0, 0, 0, p.t.Location())
+}
+
+// clause consume one clause of the expression
+func (p *relativeParser) clause() (err error) {
+	tok := p.tokens[p.i]
+	word := strings.ToLower(tok.text)
+
+	switch word {
+	case "now":
+		p.i++
+		return
+	case "today":
+		p.midnight(0)
+		p.i++
+		return
+	case "yesterday":
+		p.midnight(-1)
+		p.i++
+		return
+	case "tomorrow":
+		p.midnight(1)
+		p.i++
+		return
+	case "noon":
+		p.setClock(12, 0, 0, 0)
+		p.i++
+		return
+	case "midnight":
+		p.setClock(0, 0, 0, 0)
+		p.i++
+		return
+	case "and", "at":
+		// Connecting words
+		p.i++
+		return
+	case "ago":
+		if p.pending == len(p.offsets) {
+			return p.errorAt(tok, "nothing to apply")
+		}
+		for j := p.pending; j < len(p.offsets); j++ {
+			p.offsets[j].n = -p.offsets[j].n
+		}
+		p.pending = len(p.offsets)
+		p.i++
+		return
+	case "in":
+		p.i++
+		if err = p.offset(true); err != nil {
+			return
+		}
+		// Offsets after "in" are in the future and can't be negated
+		p.pending = len(p.offsets)
+		return
+	case "next", "last", "this":
+		return p.modifier(word)
+	case "first":
+		return p.dayOf(word)
+	}
+
+	if wd, ok := relativeWeekdays[word]; ok {
+		p.weekday("this", wd)
+		p.i++
+		return
+	}
+
+	if ok, err := p.clock(); ok || err != nil {
+		return err
+	}
+
+	if ok, err := p.anchor(); ok || err != nil {
+		return err
+	}
+
+	return p.offset(false)
+}
+
+// setClock record a clock time to apply to the anchor
+func (p *relativeParser) setClock(hour, min, sec, nsec int) {
+	p.clockSet = true
+	p.hour, p.min, p.sec, p.nsec = hour, min, sec, nsec
+}
+
+// modifier handle next, last and this followed by a weekday or unit. "last"
+// followed by "day of" is handled as the last day of a period.
+func (p *relativeParser) modifier(word string) (err error) {
+	tok := p.tokens[p.i]
+	next := p.peek(1)
+	if next == "" {
+		return p.errorAt(tok, "expected a weekday or unit after")
+	}
+	if word != "this" && next == "day" && p.peek(2) == "of" {
+		return p.dayOf(word)
+	}
+	if wd, ok := relativeWeekdays[next]; ok {
+		p.weekday(word, wd)
+		p.i += 2
+		return
+	}
+	unit, ok := ParseUnit(p.tokens[p.i+1].text)
+	if !ok {
+		return p.errorAt(p.tokens[p.i+1], "unknown weekday or unit")
+	}
+	switch word {
+	case "next":
+		p.offsets = append(p.offsets, relativeOffset{n: 1, unit: unit})
+	case "last":
+		p.offsets = append(p.offsets, relativeOffset{n: -1, unit: unit})
+	}
+	p.pending = len(p.offsets)
+	p.i += 2
+
+	return
+}
+
+// weekday move the anchor to midnight of a weekday. "next" is the first such
+// day after today, "last" the first before today and "this" today or the
+// first such day after today.
+func (p *relativeParser) weekday(word string, wd time.Weekday) {
+	diff := int(wd - p.t.Weekday())
+	switch word {
+	case "next":
+		if diff <= 0 {
+			diff += 7
+		}
+	case "last":
+		if diff >= 0 {
+			diff -= 7
+		}
+	default:
+		if diff < 0 {
+			diff += 7
+		}
+	}
+	p.midnight(diff)
+}
+
+// dayOf handle "first day of" and "last day of" followed by an optional
+// next, last or this and then month or year.
+func (p *relativeParser) dayOf(word string) (err error) {
+	tok := p.tokens[p.i]
+	if p.peek(1) != "day" || p.peek(2) != "of" {
+		return p.errorAt(tok, "expected 'day of' after")
+	}
+	p.i += 3
+
+	shift := 0
+	switch p.peek(0) {
+	case "next":
+		shift = 1
+		p.i++
+	case "last":
+		shift = -1
+		p.i++
+	case "this":
+		p.i++
+	}
+	if p.i >= len(p.tokens) {
+		return p.errorAtEnd("expected month or year")
+	}
+	unitTok := p.tokens[p.i]
+	unit, ok := ParseUnit(unitTok.text)
+	if !ok || (unit != Month && unit != Year) {
+		return p.errorAt(unitTok, "expected month or year but got")
+	}
+	p.i++
+
+	t := addUnits(p.t, shift, unit)
+	y, m, d := t.Date()
+	hour, min, sec := t.Clock()
+	switch {
+	case unit == Month && word == "first":
+		d = 1
+	case unit == Month:
+		d = daysIn(m, y)
+	case word == "first":
+		m, d = time.January, 1
+	default:
+		m, d = time.December, 31
+	}
+	p.t = time.Date(y, m, d, hour, min, sec, t.Nanosecond(), t.Location())
+
+	return
+}
+
+// anchor handle an ISO date or timestamp used as the anchor
+func (p *relativeParser) anchor() (ok bool, err error) {
+	tok := p.tokens[p.i]
+	if len(tok.text) < 8 || tok.text[0] < '0' || tok.text[0] > '9' || !strings.ContainsAny(tok.text, "-T") {
+		return false, nil
+	}
+	t, perr := ParseISOInLocation(tok.text, p.t.Location())
+	if perr != nil {
+		return true, p.errorAt(tok, "invalid date")
+	}
+	p.t = t.In(p.t.Location())
+	p.i++
+
+	return true, nil
+}
+
+// clock handle clock times such as 09:00, 9:30:15.250, 9am and 9 pm
+func (p *relativeParser) clock() (ok bool, err error) {
+	tok := p.tokens[p.i]
+	text := strings.ToLower(tok.text)
+	if text[0] < '0' || text[0] > '9' {
+		return false, nil
+	}
+
+	// A meridiem can be attached or be the next word
+	meridiem := ""
+	consumed := 1
+	switch {
+	case strings.HasSuffix(text, "am"), strings.HasSuffix(text, "pm"):
+		meridiem = text[len(text)-2:]
+		text = text[:len(text)-2]
+	case p.peek(1) == "am" || p.peek(1) == "pm":
+		meridiem = p.peek(1)
+		consumed = 2
+	}
+	if meridiem == "" && !strings.Contains(text, ":") {
+		return false, nil
+	}
+
+	hour, min, sec, nsec, perr := parseRelativeClock(text)
+	if perr != nil {
+		return true, p.errorAt(tok, "invalid clock time")
+	}
+	if meridiem != "" {
+		if hour < 1 || hour > 12 {
+			return true, p.errorAt(tok, "invalid 12 hour clock time")
+		}
+		hour = hour % 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	}
+	p.setClock(hour, min, sec, nsec)
+	p.i += consumed
+
+	return true, nil
+}
+
+// parseRelativeClock parse h[:mm[:ss[.fff]]]
+func parseRelativeClock(text string) (hour, min, sec, nsec int, err error) {
+	parts := strings.Split(text, ":")
+	if len(parts) > 3 || len(parts[0]) == 0 || len(parts[0]) > 2 {
+		err = errCannotParseNumber
+		return
+	}
+	if hour, err = strconv.Atoi(parts[0]); err != nil {
+		return
+	}
+	if len(parts) > 1 {
+		if len(parts[1]) != 2 {
+			err = errCannotParseNumber
+			return
+		}
+		if min, err = atoi2(parts[1]); err != nil {
+			return
+		}
+	}
+	if len(parts) > 2 {
+		secPart := parts[2]
+		if dot := strings.IndexByte(secPart, '.'); dot >= 0 {
+			frac := secPart[dot+1:]
+			secPart = secPart[:dot]
+			if len(frac) == 0 || len(frac) > 9 {
+				err = errCannotParseNumber
+				return
+			}
+			if nsec, err = strconv.Atoi(frac); err != nil {
+				return
+			}
+			nsec *= intPow(10, 9-len(frac))
+		}
+		if len(secPart) != 2 {
+			err = errCannotParseNumber
+			return
+		}
+		if sec, err = atoi2(secPart); err != nil {
+			return
+		}
+	}
+	if hour > 23 || min > 59 || sec > 59 || nsec < 0 {
+		err = errCannotParseNumber
+	}
+
+	return
+}
+
+// offset handle "N unit", "Nunit", "+Nunit", "-N unit", "a unit" and "an
+// unit". Several may follow "in" or precede "ago".
+func (p *relativeParser) offset(required bool) (err error) {
+	start := len(p.offsets)
+	for p.i < len(p.tokens) {
+		tok := p.tokens[p.i]
+		text := tok.text
+
+		var n int
+		var unitText string
+		consumed := 1
+
+		switch lower := strings.ToLower(text); {
+		case lower == "a" || lower == "an":
+			n = 1
+			if p.i+1 >= len(p.tokens) {
+				return p.errorAtEnd("expected unit")
+			}
+			unitText = p.tokens[p.i+1].text
+			consumed = 2
+		default:
+			// Split into sign, digits and an optional attached unit
+			j := 0
+			if j < len(text) && (text[j] == '+' || text[j] == '-') {
+				j++
+			}
+			k := j
+			for k < len(text) && text[k] >= '0' && text[k] <= '9' {
+				k++
+			}
+			if k == j {
+				if len(p.offsets) > start {
+					// Not an offset; leave it for the next clause
+					return
+				}
+				if required {
+					return p.errorAt(tok, "expected a number but got")
+				}
+				return p.errorAt(tok, "could not parse")
+			}
+			n, err = strconv.Atoi(text[:k])
+			if err != nil {
+				return p.errorAt(tok, "number out of range")
+			}
+			unitText = text[k:]
+			if unitText == "" {
+				if p.i+1 >= len(p.tokens) {
+					return p.errorAtEnd("expected unit")
+				}
+				unitText = p.tokens[p.i+1].text
+				consumed = 2
+			}
+		}
+
+		unit, ok := ParseUnit(unitText)
+		if !ok {
+			if len(p.offsets) > start {
+				// Likely a clock time such as 9am; leave it for the next
+				// clause
+				return nil
+			}
+			return p.errorAt(p.tokens[p.i+consumed-1], "unknown unit")
+		}
+		if _, ok := unitsDuration(n, unit); !ok && !unit.IsCalendar() {
+			return p.errorAt(tok, "offset too large")
+		}
+		p.offsets = append(p.offsets, relativeOffset{n: n, unit: unit})
+		p.i += consumed
+
+		// "and" may join offsets, as in "1 day and 2 hours ago"
+		if p.peek(0) == "and" {
+			p.i++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,103 @@
+package timestamp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestParseRelative(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err) // Location should load
+
+	// Wednesday
+	ref := time.Date(2024, 1, 31, 15, 30, 0, 0, toronto)
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"now", ref},
+		{"today", time.Date(2024, 1, 31, 0, 0, 0, 0, toronto)},
+		{"yesterday 09:00", time.Date(2024, 1, 30, 9, 0, 0, 0, toronto)},
+		{"tomorrow 9pm", time.Date(2024, 2, 1, 21, 0, 0, 0, toronto)},
+		{"tomorrow at 9 am", time.Date(2024, 2, 1, 9, 0, 0, 0, toronto)},
+		{"3 days ago", time.Date(2024, 1, 28, 15, 30, 0, 0, toronto)},
+		{"3 days ago 09:00:30.5", time.Date(2024, 1, 28, 9, 0, 30, 500000000, toronto)},
+		{"2 hours 30 minutes ago", time.Date(2024, 1, 31, 13, 0, 0, 0, toronto)},
+		{"1 day and 2 hours ago", time.Date(2024, 1, 30, 13, 30, 0, 0, toronto)},
+		{"an hour ago", time.Date(2024, 1, 31, 14, 30, 0, 0, toronto)},
+		{"in 2 hours", time.Date(2024, 1, 31, 17, 30, 0, 0, toronto)},
+		{"+1d -90m", time.Date(2024, 2, 1, 14, 0, 0, 0, toronto)},
+		{"next monday", time.Date(2024, 2, 5, 0, 0, 0, 0, toronto)},
+		{"last wednesday", time.Date(2024, 1, 24, 0, 0, 0, 0, toronto)},
+		{"this wednesday", time.Date(2024, 1, 31, 0, 0, 0, 0, toronto)},
+		{"friday noon", time.Date(2024, 2, 2, 12, 0, 0, 0, toronto)},
+		{"next month", time.Date(2024, 2, 29, 15, 30, 0, 0, toronto)},
+		{"last day of next month", time.Date(2024, 2, 29, 15, 30, 0, 0, toronto)},
+		{"first day of last month midnight", time.Date(2023, 12, 1, 0, 0, 0, 0, toronto)},
+		{"last day of this year", time.Date(2024, 12, 31, 15, 30, 0, 0, toronto)},
+		{"2024-03-01 09:00", time.Date(2024, 3, 1, 9, 0, 0, 0, toronto)},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.ParseRelative(test.expr, ref, toronto)
+		is.NoErr(err) // Expression should parse
+		t.Logf("expr %q got %v", test.expr, got)
+		is.True(got.Equal(test.expected)) // Result should match expected
+	}
+}
+
+func TestParseRelativeDST(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err) // Location should load
+
+	// Clocks go forward at 02:00 on 2024-03-10
+	ref := time.Date(2024, 3, 9, 12, 0, 0, 0, toronto)
+
+	got, err := timestamp.ParseRelative("in 1 day", ref, toronto)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2024, 3, 10, 12, 0, 0, 0, toronto))) // Day step keeps wall clock
+	is.Equal(got.Sub(ref), 23*time.Hour)                             // The day was 23 hours long
+
+	got, err = timestamp.ParseRelative("in 24 hours", ref, toronto)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2024, 3, 10, 13, 0, 0, 0, toronto))) // Hours are exact
+}
+
+func TestParseRelativeErrors(t *testing.T) {
+	is := is.New(t)
+
+	ref := time.Date(2024, 1, 31, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr  string
+		token string
+	}{
+		{"3 fortnights ago", "'fortnights'@2"},
+		{"yesterday banana", "'banana'@10"},
+		{"next blursday", "'blursday'@5"},
+		{"25:00", "'25:00'@0"},
+		{"ago", "'ago'@0"},
+		// Offsets that don't fit in a duration
+		{"in 3000000 hours", "'3000000'@3"},
+		{"1 day and 200000000000s ago", "'200000000000s'@10"},
+	}
+
+	for _, test := range tests {
+		_, err := timestamp.ParseRelative(test.expr, ref, time.UTC)
+		is.True(err != nil) // Should be an error
+		t.Logf("expr %q error %v", test.expr, err)
+		is.True(strings.Contains(err.Error(), test.token)) // Error should point at the token
+	}
+
+	_, err := timestamp.ParseRelative("", ref, time.UTC)
+	is.True(err != nil) // Empty expression should be an error
+}
@@ -0,0 +1,134 @@
+package timestamp
+
+import "time"
+
+// RoundingMode how Round chooses between the start of the unit containing a
+// time and the start of the next unit
+type RoundingMode int
+
+// Rounding modes
+const (
+	RoundHalfUp   RoundingMode = iota // nearest, with halfway going to the next unit
+	RoundHalfDown                     // nearest, with halfway staying in its unit
+	RoundHalfEven                     // nearest, with halfway going to the even numbered unit
+	RoundDown                         // the start of the unit, as StartOf gives
+	RoundUp                           // the start of the next unit unless already at a start
+)
+
+// StartOf get the first instant of the unit containing t in the location of
+// t, such as the start of its month or millisecond. Weeks start on Monday as
+// in ISO-8601. Calendar units start at midnight, or at the first instant of
+// the day when a daylight saving change skips midnight. Unlike time.Truncate
+// the units follow the wall clock of the location rather than UTC.
+func StartOf(t time.Time, unit Unit) time.Time {
+	return startOfUnit(t, unit)
+}
+
+// EndOf get the last instant of the unit containing t in the location of t,
+// which is a nanosecond before the start of the next unit
+func EndOf(t time.Time, unit Unit) time.Time {
+	return endOfUnit(t, unit)
+}
+
+// StartOfWeek get the first instant of the week containing t for weeks that
+// start on a given day, such as time.Sunday in North America
+func StartOfWeek(t time.Time, first time.Weekday) time.Time {
+	return startOfWeek(t, first)
+}
+
+// EndOfWeek get the last instant of the week containing t for weeks that
+// start on a given day
+func EndOfWeek(t time.Time, first time.Weekday) time.Time {
+	start := startOfWeek(t, first)
+	return firstInstant(DateOf(start).AddDays(7), t.Location()).Add(-time.Nanosecond)
+}
+
+// StartOfHalf get the first instant of the half year containing t, which is
+// January 1 or July 1
+func StartOfHalf(t time.Time) time.Time {
+	d := Date{Year: t.Year(), Month: time.January, Day: 1}
+	if t.Month() > time.June {
+		d.Month = time.July
+	}
+	return firstInstant(d, t.Location())
+}
+
+// EndOfHalf get the last instant of the half year containing t
+func EndOfHalf(t time.Time) time.Time {
+	start := DateOf(StartOfHalf(t))
+	return firstInstant(start.AddMonths(6), t.Location()).Add(-time.Nanosecond)
+}
+
+// Round round t to the start of a unit in the location of t. The nearer of
+// the start of the unit containing t and the start of the next unit is
+// chosen, with the mode deciding ties, or the mode picks one directly with
+// RoundDown and RoundUp. Days across a daylight saving change are measured in
+// elapsed time, so the halfway point of a 25 hour day is 11:30 on the wall
+// clock.
+//
+// With RoundHalfEven a tie goes to whichever of the two starts has an even
+// unit number. Exact units are numbered by wall clock time since 1970, days
+// and weeks by the date and months, quarters and years by the calendar.
+func Round(t time.Time, unit Unit, mode RoundingMode) time.Time {
+	floor := startOfUnit(t, unit)
+	if floor.Equal(t) {
+		return t
+	}
+	ceil := nextStartOfUnit(floor, unit)
+
+	switch mode {
+	case RoundDown:
+		return floor
+	case RoundUp:
+		return ceil
+	}
+
+	below, above := t.Sub(floor), ceil.Sub(t)
+	switch {
+	case below < above:
+		return floor
+	case below > above:
+		return ceil
+	case mode == RoundHalfDown:
+		return floor
+	case mode == RoundHalfEven && unitNumber(floor, unit)&1 == 0:
+		return floor
+	}
+
+	return ceil
+}
+
+// epochMonday the first Monday of 1970, which numbers weeks
+var epochMonday = Date{Year: 1970, Month: time.January, Day: 5}
+
+// unitNumber number the unit starting at start so that consecutive units
+// alternate between even and odd
+func unitNumber(start time.Time, unit Unit) int64 {
+	_, offset := start.Zone()
+	wall := start.Unix() + int64(offset)
+
+	switch unit {
+	case Microsecond:
+		return wall*1e6 + int64(start.Nanosecond())/1e3
+	case Millisecond:
+		return wall*1e3 + int64(start.Nanosecond())/1e6
+	case Second:
+		return wall
+	case Minute:
+		return wall / 60
+	case Hour:
+		return wall / 3600
+	case Day:
+		return int64(DateOf(start).DaysSince(epochMonday))
+	case Week:
+		return int64(DateOf(start).DaysSince(epochMonday) / 7)
+	case Month:
+		return int64(start.Year())*12 + int64(start.Month()) - 1
+	case Quarter:
+		return int64(start.Year())*4 + int64(start.Month()-1)/3
+	case Year:
+		return int64(start.Year())
+	}
+
+	return int64(start.Nanosecond())
+}
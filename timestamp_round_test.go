@@ -0,0 +1,101 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestStartOfEndOf(t *testing.T) {
+	is := is.New(t)
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	is.NoErr(err)
+
+	// A Wednesday in Q3 at a half hour offset
+	tm := time.Date(2024, 8, 14, 15, 47, 12, 123456789, kolkata)
+
+	tests := []struct {
+		unit  timestamp.Unit
+		start string
+		end   string
+	}{
+		{timestamp.Microsecond, "2024-08-14T15:47:12.123456+05:30", "2024-08-14T15:47:12.123456999+05:30"},
+		{timestamp.Millisecond, "2024-08-14T15:47:12.123+05:30", "2024-08-14T15:47:12.123999999+05:30"},
+		{timestamp.Hour, "2024-08-14T15:00:00+05:30", "2024-08-14T15:59:59.999999999+05:30"},
+		{timestamp.Day, "2024-08-14T00:00:00+05:30", "2024-08-14T23:59:59.999999999+05:30"},
+		{timestamp.Week, "2024-08-12T00:00:00+05:30", "2024-08-18T23:59:59.999999999+05:30"},
+		{timestamp.Month, "2024-08-01T00:00:00+05:30", "2024-08-31T23:59:59.999999999+05:30"},
+		{timestamp.Quarter, "2024-07-01T00:00:00+05:30", "2024-09-30T23:59:59.999999999+05:30"},
+		{timestamp.Year, "2024-01-01T00:00:00+05:30", "2024-12-31T23:59:59.999999999+05:30"},
+	}
+
+	for _, test := range tests {
+		is.Equal(timestamp.StartOf(tm, test.unit).Format(time.RFC3339Nano), test.start) // Start should match
+		is.Equal(timestamp.EndOf(tm, test.unit).Format(time.RFC3339Nano), test.end)     // End should match
+	}
+
+	is.Equal(timestamp.StartOfWeek(tm, time.Sunday).Format(time.RFC3339), "2024-08-11T00:00:00+05:30")
+	is.Equal(timestamp.EndOfWeek(tm, time.Sunday).Format(time.RFC3339), "2024-08-17T23:59:59+05:30")
+	is.Equal(timestamp.StartOfWeek(tm, time.Wednesday).Format(time.RFC3339), "2024-08-14T00:00:00+05:30")
+	is.Equal(timestamp.StartOfHalf(tm).Format(time.RFC3339), "2024-07-01T00:00:00+05:30")
+	is.Equal(timestamp.EndOfHalf(tm).Format(time.RFC3339), "2024-12-31T23:59:59+05:30")
+	is.Equal(timestamp.StartOfHalf(tm.AddDate(0, -3, 0)).Format(time.RFC3339), "2024-01-01T00:00:00+05:30")
+
+	// Midnight is skipped in Santiago on 2022-09-11
+	santiago, err := time.LoadLocation("America/Santiago")
+	is.NoErr(err)
+	tm = time.Date(2022, 9, 11, 15, 0, 0, 0, santiago)
+	is.Equal(timestamp.StartOf(tm, timestamp.Day).Format(time.RFC3339), "2022-09-11T01:00:00-03:00")
+	is.Equal(timestamp.EndOf(tm.AddDate(0, 0, -1), timestamp.Day).Format(time.RFC3339), "2022-09-10T23:59:59-04:00")
+	is.Equal(timestamp.StartOf(tm, timestamp.Week).Format(time.RFC3339), "2022-09-05T00:00:00-04:00")
+	is.Equal(timestamp.EndOf(tm, timestamp.Day).Format(time.RFC3339), "2022-09-11T23:59:59-03:00")
+}
+
+func TestRound(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ms := func(micros int) time.Time {
+		return base.Add(time.Duration(micros) * time.Microsecond)
+	}
+
+	tests := []struct {
+		t        time.Time
+		unit     timestamp.Unit
+		mode     timestamp.RoundingMode
+		expected time.Time
+	}{
+		{ms(1500), timestamp.Millisecond, timestamp.RoundHalfUp, ms(2000)},
+		{ms(1500), timestamp.Millisecond, timestamp.RoundHalfDown, ms(1000)},
+		{ms(1500), timestamp.Millisecond, timestamp.RoundHalfEven, ms(2000)},
+		{ms(2500), timestamp.Millisecond, timestamp.RoundHalfEven, ms(2000)},
+		{ms(2501), timestamp.Millisecond, timestamp.RoundHalfEven, ms(3000)},
+		{ms(2100), timestamp.Millisecond, timestamp.RoundUp, ms(3000)},
+		{ms(2900), timestamp.Millisecond, timestamp.RoundDown, ms(2000)},
+		{ms(2000), timestamp.Millisecond, timestamp.RoundUp, ms(2000)},
+		{base.Add(time.Nanosecond * 500), timestamp.Microsecond, timestamp.RoundHalfEven, base},
+		{base, timestamp.Day, timestamp.RoundHalfUp, base.AddDate(0, 0, 1).Add(-12 * time.Hour)},
+		{base, timestamp.Day, timestamp.RoundHalfDown, base.Add(-12 * time.Hour)},
+		// 2024-01-01 is day 19719 since the first Monday of 1970, which is odd
+		{time.Date(2024, 1, 16, 11, 0, 0, 0, time.UTC), timestamp.Month, timestamp.RoundHalfUp, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{base, timestamp.Day, timestamp.RoundHalfEven, base.Add(12 * time.Hour)},
+		{time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC), timestamp.Month, timestamp.RoundHalfUp, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC), timestamp.Month, timestamp.RoundHalfDown, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2024, 7, 2, 0, 0, 0, 0, time.UTC), timestamp.Year, timestamp.RoundHalfUp, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// The 25 hour day of 2024-11-03 has its halfway point at 11:30 wall clock
+		{time.Date(2024, 11, 3, 11, 20, 0, 0, toronto), timestamp.Day, timestamp.RoundHalfUp, time.Date(2024, 11, 3, 0, 0, 0, 0, toronto)},
+		{time.Date(2024, 11, 3, 11, 40, 0, 0, toronto), timestamp.Day, timestamp.RoundHalfUp, time.Date(2024, 11, 4, 0, 0, 0, 0, toronto)},
+	}
+
+	for _, test := range tests {
+		got := timestamp.Round(test.t, test.unit, test.mode)
+		t.Logf("%v %v got %v", test.t.Format(time.RFC3339Nano), test.unit, got.Format(time.RFC3339Nano))
+		is.True(got.Equal(test.expected)) // Rounded time should match
+	}
+}
@@ -0,0 +1,230 @@
+package timestamp
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// Shape the textual style of a parsed timestamp: its layout, separators,
+// precision and offset style. A Shape is obtained from ParseWithShape and
+// formats other instants the same way the input was written. The zero Shape
+// formats nothing.
+type Shape struct {
+	layout []layoutField
+	utc    bool // the layout names GMT so times are written in UTC
+}
+
+// shapeError make an error for an input whose shape can't be determined
+func shapeError(reason string, timeStr string) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.ParseWithShape: ").S(reason).S(" in input ").S(timeStr)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// ParseWithShape parse a timestamp as ParseInLocation does and also return
+// the shape of the input. Formatting the parsed time with the shape gives the
+// input back, and formatting a different time gives it in the same style.
+// This keeps the style of each timestamp when rewriting logs, for example
+// when converting zones or shifting times.
+//
+// ISO-8601 inputs keep their separators, fraction digits and offset style.
+// Unix inputs keep their digit count, so a millisecond timestamp stays in
+// milliseconds. Other inputs keep the layout that parsed them.
+//
+// An error is returned if the input can't be parsed or if it can't be
+// reproduced exactly, such as when a month name is in lower case.
+func ParseWithShape(timeStr string, location *time.Location) (t time.Time, shape Shape, err error) {
+	if location == nil {
+		location = time.UTC
+	}
+	trimmed := strings.TrimSpace(timeStr)
+
+	t, matched, err := parseTimestampFormat(trimmed, location, false)
+	if err != nil {
+		return
+	}
+
+	switch matched {
+	case matchedISO:
+		shape.layout = isoShape(trimmed)
+	case matchedUnix:
+		shape.layout = unixShape(trimmed)
+	default:
+		format := nonISOTimeFormats[matched]
+		shape.layout = compileGoLayout(format)
+		shape.utc = strings.HasSuffix(format, " GMT")
+	}
+
+	if shape.layout == nil || shape.Format(t) != trimmed {
+		err = shapeError("could not reproduce the style", timeStr)
+		shape = Shape{}
+	}
+
+	return
+}
+
+// Format format a time in the style of the shape. The time is written in its
+// own location, so convert it first if the output should be in a different
+// zone. Shapes with no offset write the wall clock of the time. Shapes that
+// name GMT always write the time in UTC.
+func (s Shape) Format(t time.Time) string {
+	if s.utc {
+		t = t.In(time.UTC)
+	}
+	return formatLayout(t, s.layout)
+}
+
+// Append append a time formatted in the style of the shape to b and return the
+// extended slice
+func (s Shape) Append(b []byte, t time.Time) []byte {
+	if s.utc {
+		t = t.In(time.UTC)
+	}
+	return appendLayout(b, t, s.layout)
+}
+
+// IsZero is this the zero Shape
+func (s Shape) IsZero() bool {
+	return s.layout == nil
+}
+
+// unixShape get the shape of a Unix timestamp. Digits after the first 10 or
+// after a decimal point are a fraction of a second, matching ParseUnixTS.
+func unixShape(timeStr string) []layoutField {
+	layout := []layoutField{{kind: fieldUnix}}
+	if i := strings.IndexByte(timeStr, '.'); i >= 0 {
+		digits := len(timeStr) - i - 1
+		if digits < 1 || digits > 9 {
+			return nil
+		}
+		layout = appendLiteral(layout, ".")
+		return append(layout, layoutField{kind: fieldFraction, width: digits})
+	}
+	if digits := len(timeStr) - 10; digits > 0 {
+		if digits > 9 {
+			return nil
+		}
+		layout = append(layout, layoutField{kind: fieldFraction, width: digits})
+	}
+
+	return layout
+}
+
+// isoShapeKinds the field for the digits of each ISO lexer section
+var isoShapeKinds = [...]fieldKind{
+	yearSection:      fieldYear,
+	monthSection:     fieldMonth,
+	daySection:       fieldDay,
+	hourSection:      fieldHour,
+	minuteSection:    fieldMinute,
+	secondSection:    fieldSecond,
+	subsecondSection: fieldFraction,
+}
+
+// isoShapeWidths the number of digits in each ISO lexer section
+var isoShapeWidths = [...]int{
+	yearSection:      4,
+	monthSection:     2,
+	daySection:       2,
+	hourSection:      2,
+	minuteSection:    2,
+	secondSection:    2,
+	subsecondSection: 9,
+}
+
+// isoShape get the shape of an ISO timestamp. Digits are assigned to sections
+// the way lexISOTimestamp assigns them and everything else between them is
+// kept as literal text. Nil is returned for inputs whose shape can't be
+// described.
+func isoShape(timeStr string) (layout []layoutField) {
+	section, count := yearSection, 0
+	extended := false // were - or : separators used
+
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		f := layoutField{kind: isoShapeKinds[section], width: count, pad: '0'}
+		if section == subsecondSection {
+			f.pad = 0
+		}
+		layout = append(layout, f)
+		count = 0
+	}
+
+	for i := 0; i < len(timeStr); i++ {
+		c := timeStr[i]
+		switch {
+		case c >= '0' && c <= '9':
+			if section > subsecondSection {
+				return nil
+			}
+			count++
+			if count == isoShapeWidths[section] {
+				flush()
+				section++
+			}
+		case (c == '+' || c == '-' || c == 'Z') && section >= subsecondSection:
+			flush()
+			flags, ok := isoShapeOffset(timeStr[i:], extended)
+			if !ok {
+				return nil
+			}
+			return append(layout, layoutField{kind: fieldOffset, flags: flags})
+		default:
+			if c == '-' || c == ':' {
+				extended = true
+			}
+			if count > 0 && section != subsecondSection {
+				// A section ended early
+				return nil
+			}
+			layout = appendLiteral(layout, timeStr[i:i+1])
+		}
+	}
+	if count > 0 && section != subsecondSection {
+		return nil
+	}
+	flush()
+
+	return
+}
+
+// isoShapeOffset get the offset style of the zone at the end of an ISO
+// timestamp. A Z is written as Z for a zero offset and otherwise in the form
+// of the rest of the timestamp.
+func isoShapeOffset(zone string, extended bool) (flags int, ok bool) {
+	if zone == "Z" {
+		if extended {
+			return offsetZ | offsetColon, true
+		}
+		return offsetZ, true
+	}
+
+	digits := zone[1:]
+	switch {
+	case len(digits) == 2:
+		return offsetNoMinutes, isDigits(digits)
+	case len(digits) == 4:
+		return 0, isDigits(digits)
+	case len(digits) == 5 && digits[2] == ':':
+		return offsetColon, isDigits(digits[:2]) && isDigits(digits[3:])
+	}
+
+	return 0, false
+}
+
+// isDigits is s made up only of ASCII digits
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
@@ -0,0 +1,75 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestParseWithShape(t *testing.T) {
+	is := is.New(t)
+
+	other := time.Date(2023, 11, 5, 7, 8, 9, 987654321, time.FixedZone("", -(3*60*60+30*60)))
+
+	tests := []struct {
+		input    string
+		expected string // other formatted with the shape of input
+	}{
+		{"2006-01-02T15:04:05-07:00", "2023-11-05T07:08:09-03:30"},
+		{"2006-01-02T15:04:05.000-07:00", "2023-11-05T07:08:09.987-03:30"},
+		{"2006-01-02T15:04:05.123456Z", "2023-11-05T07:08:09.987654-03:30"},
+		{"2006-01-02 15:04:05.1+0000", "2023-11-05 07:08:09.9-0330"},
+		{"20060102T150405-0700", "20231105T070809-0330"},
+		{"20060102T150405.000-0700", "20231105T070809.987-0330"},
+		{"20060102T150405Z", "20231105T070809-0330"},
+		{"2006-01-02T15:04:05", "2023-11-05T07:08:09"},
+		{"2006-01-02", "2023-11-05"},
+		{"2006/01/02 15:04:05", "2023/11/05 07:08:09"},
+		{"2006-01-02T15:04:05+07", "2023-11-05T07:08:09-03"},
+		{"1136214245", "1699180689"},
+		{"1136214245123", "1699180689987"},
+		{"1136214245123456789", "1699180689987654321"},
+		{"1136214245.12", "1699180689.98"},
+		{"Mon, 02 Jan 2006 15:04:05 -0700", "Sun, 05 Nov 2023 07:08:09 -0330"},
+		{"Mon, 02 Jan 2006 15:04:05 GMT", "Sun, 05 Nov 2023 10:38:09 GMT"},
+		{"Monday, 02-Jan-2006 15:04:05", "Sunday, 05-Nov-2023 07:08:09"},
+		{"02 Jan 06 15:04 -0700", "05 Nov 23 07:08 -0330"},
+		{"01/02/2006", "11/05/2023"},
+		{"1/2/2006", "11/5/2023"},
+	}
+
+	for _, test := range tests {
+		parsed, shape, err := timestamp.ParseWithShape(test.input, time.UTC)
+		is.NoErr(err) // Input should parse with a shape
+		t.Logf("%s parsed %v reformatted %s", test.input, parsed, shape.Format(other))
+		is.Equal(shape.Format(parsed), test.input)   // Shape should reproduce the input
+		is.Equal(shape.Format(other), test.expected) // Shape should apply to other times
+		is.Equal(string(shape.Append([]byte(">"), other)), ">"+test.expected)
+
+		plain, err := timestamp.ParseInUTC(test.input)
+		is.NoErr(err)
+		is.True(plain.Equal(parsed)) // Parsed time should match ParseInUTC
+	}
+}
+
+func TestParseWithShapeConvertZone(t *testing.T) {
+	is := is.New(t)
+
+	line := "2021-03-04T05:06:07.890+05:30"
+	parsed, shape, err := timestamp.ParseWithShape(line, nil)
+	is.NoErr(err)
+	is.Equal(shape.Format(parsed.In(time.UTC).Add(time.Hour)), "2021-03-04T00:36:07.890+00:00")
+}
+
+func TestParseWithShapeErrors(t *testing.T) {
+	is := is.New(t)
+
+	for _, input := range []string{"", "not a time", "mon, 02 jan 2006 15:04:05 -0700"} {
+		_, shape, err := timestamp.ParseWithShape(input, time.UTC)
+		t.Logf("%q: %v", input, err)
+		is.True(err != nil)     // Input should not give a shape
+		is.True(shape.IsZero()) // Shape should be zero on error
+	}
+}
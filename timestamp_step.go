@@ -0,0 +1,209 @@
+package timestamp
+
+import (
+	"errors"
+	"iter"
+	"time"
+)
+
+// stepKind what a Step moves by
+type stepKind int
+
+const (
+	stepDuration stepKind = iota + 1 // a fixed duration
+	stepUnits                        // a number of calendar units
+	stepPeriod                       // a period of months, days and clock
+)
+
+// Step an amount a StepRange moves by: a fixed duration, a number of
+// calendar units or a Period. The zero Step is not valid.
+type Step struct {
+	kind     stepKind
+	duration time.Duration
+	count    int
+	unit     Unit
+	period   Period
+}
+
+// StepDuration step by a fixed duration. A day is always 24 hours.
+func StepDuration(d time.Duration) Step {
+	return Step{kind: stepDuration, duration: d}
+}
+
+// StepUnits step by a number of units. Calendar units keep the wall clock, so
+// a day across a daylight saving change is 23 or 25 hours, and months are
+// clamped to the end of the month.
+func StepUnits(n int, unit Unit) Step {
+	if !unit.IsCalendar() {
+		return StepDuration(time.Duration(n) * unit.Duration())
+	}
+	return Step{kind: stepUnits, count: n, unit: unit}
+}
+
+// StepPeriod step by a period, such as one parsed from an ISO-8601 period
+// like P1M or PT15M with ParsePostgresInterval
+func StepPeriod(p Period) Step {
+	return Step{kind: stepPeriod, period: p}
+}
+
+// times get the time k steps from t, with a negative k stepping back.
+// Stepping k times from the origin rather than once from the previous time
+// keeps month steps from drifting after a short month.
+func (s Step) times(t time.Time, k int) time.Time {
+	switch s.kind {
+	case stepDuration:
+		return t.Add(time.Duration(k) * s.duration)
+	case stepUnits:
+		return addUnits(t, k*s.count, s.unit)
+	case stepPeriod:
+		p := Period{Months: k * s.period.Months, Days: k * s.period.Days, Clock: time.Duration(k) * s.period.Clock}
+		return p.AddTo(t, nil)
+	}
+	return t
+}
+
+// align move t to a boundary of the step, later if up is set and otherwise
+// earlier. Calendar steps align to the start of their unit and periods to the
+// start of a month or day. Fixed durations align to whole multiples of the
+// duration from midnight.
+func (s Step) align(t time.Time, up bool) time.Time {
+	var unit Unit
+	d := s.duration
+	switch {
+	case s.kind == stepUnits:
+		unit = s.unit
+	case s.kind == stepPeriod && s.period.Months != 0:
+		unit = Month
+	case s.kind == stepPeriod && s.period.Days != 0:
+		unit = Day
+	case s.kind == stepPeriod:
+		d = s.period.Clock
+	}
+
+	var boundary time.Time
+	if unit != 0 {
+		boundary = startOfUnit(t, unit)
+		if up && boundary.Before(t) {
+			boundary = addUnits(boundary, 1, unit)
+		}
+		return boundary
+	}
+
+	midnight := startOfUnit(t, Day)
+	since := t.Sub(midnight)
+	boundary = midnight.Add(since - since%d)
+	if up && boundary.Before(t) {
+		boundary = boundary.Add(d)
+	}
+
+	return boundary
+}
+
+// StepRange walks from Start to End by a step. Calendar steps are taken in
+// the location of Start. Start and End are included when a step lands on
+// them unless Exclusive is set, which leaves out End.
+//
+// With Reverse the walk starts at End and steps back toward Start. With Align
+// the first time is moved to a boundary of the step, forward from Start or
+// back from End, so a range of hours starting at 10:20 gives 11:00, 12:00 and
+// so on.
+type StepRange struct {
+	Start     time.Time
+	End       time.Time
+	Step      Step
+	Exclusive bool // leave out End
+	Reverse   bool // walk from End back to Start
+	Align     bool // begin on a boundary of the step
+}
+
+// Validate check that the step moves forward
+func (r StepRange) Validate() error {
+	switch {
+	case r.Step.kind == 0:
+		return errors.New("timestamp.StepRange: no step set")
+	case r.Step.kind == stepDuration && r.Step.duration <= 0,
+		r.Step.kind == stepUnits && r.Step.count <= 0,
+		r.Step.kind == stepPeriod && !r.Step.times(r.Start, 1).After(r.Start):
+		return errors.New("timestamp.StepRange: step must move forward")
+	}
+	return nil
+}
+
+// Iterator get a function returning each time in the range in turn. After the
+// last time it returns false.
+//
+// Sample usage:
+/*
+	next, err := timestamp.StepRange{Start: t1, End: t2, Step: timestamp.StepUnits(1, timestamp.Month)}.Iterator()
+	if err != nil {
+		// Handle a step that does not move forward
+	}
+	for t, ok := next(); ok; t, ok = next() {
+		fmt.Println(t)
+	}
+*/
+func (r StepRange) Iterator() (next func() (time.Time, bool), err error) {
+	if err = r.Validate(); err != nil {
+		return
+	}
+
+	location := r.Start.Location()
+	origin, sign := r.Start, 1
+	if r.Reverse {
+		origin, sign = r.End.In(location), -1
+	}
+	if r.Align {
+		origin = r.Step.align(origin, !r.Reverse)
+	}
+
+	k, done := 0, false
+	var previous time.Time
+	next = func() (time.Time, bool) {
+		for !done {
+			t := r.Step.times(origin, sign*k)
+			// A step that lands on the previous time, such as a month that
+			// clamps twice to the same day, would never finish
+			if k > 0 && (sign > 0 && !t.After(previous) || sign < 0 && !t.Before(previous)) {
+				done = true
+				break
+			}
+			k++
+			previous = t
+
+			switch {
+			case r.Exclusive && t.Equal(r.End):
+				if !r.Reverse {
+					done = true
+				}
+				continue
+			case !r.Reverse && t.After(r.End), r.Reverse && t.Before(r.Start):
+				done = true
+				continue
+			}
+			return t, true
+		}
+		return time.Time{}, false
+	}
+
+	return
+}
+
+// All get the times in the range for use in a range over func loop. Nothing
+// is yielded for a range that does not validate.
+//
+//	for t := range timestamp.StepRange{Start: t1, End: t2, Step: timestamp.StepDuration(time.Hour)}.All() {
+//		fmt.Println(t)
+//	}
+func (r StepRange) All() iter.Seq[time.Time] {
+	return func(yield func(time.Time) bool) {
+		next, err := r.Iterator()
+		if err != nil {
+			return
+		}
+		for t, ok := next(); ok; t, ok = next() {
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package timestamp_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+// formatTimes format times for comparison
+func formatTimes(times []time.Time, layout string) (formatted []string) {
+	for _, t := range times {
+		formatted = append(formatted, t.Format(layout))
+	}
+	return
+}
+
+func TestStepRange(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	jan31 := time.Date(2024, 1, 31, 10, 0, 0, 0, toronto)
+	quarterHour, err := timestamp.ParsePostgresInterval("PT15M", timestamp.IntervalISO8601)
+	is.NoErr(err)
+
+	tests := []struct {
+		name     string
+		r        timestamp.StepRange
+		layout   string
+		expected []string
+	}{
+		{
+			"months clamp without drifting",
+			timestamp.StepRange{Start: jan31, End: jan31.AddDate(0, 4, 0), Step: timestamp.StepUnits(1, timestamp.Month)},
+			"2006-01-02",
+			[]string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30", "2024-05-31"},
+		},
+		{
+			"days keep the wall clock across a change",
+			timestamp.StepRange{Start: time.Date(2024, 3, 9, 10, 0, 0, 0, toronto), End: time.Date(2024, 3, 11, 10, 0, 0, 0, toronto), Step: timestamp.StepUnits(1, timestamp.Day)},
+			time.RFC3339,
+			[]string{"2024-03-09T10:00:00-05:00", "2024-03-10T10:00:00-04:00", "2024-03-11T10:00:00-04:00"},
+		},
+		{
+			"exclusive end",
+			timestamp.StepRange{Start: jan31, End: jan31.Add(3 * time.Hour), Step: timestamp.StepDuration(time.Hour), Exclusive: true},
+			"15:04",
+			[]string{"10:00", "11:00", "12:00"},
+		},
+		{
+			"reverse",
+			timestamp.StepRange{Start: jan31, End: jan31.Add(3 * time.Hour), Step: timestamp.StepUnits(1, timestamp.Hour), Reverse: true},
+			"15:04",
+			[]string{"13:00", "12:00", "11:00", "10:00"},
+		},
+		{
+			"reverse exclusive leaves out the end",
+			timestamp.StepRange{Start: jan31, End: jan31.Add(3 * time.Hour), Step: timestamp.StepUnits(1, timestamp.Hour), Reverse: true, Exclusive: true},
+			"15:04",
+			[]string{"12:00", "11:00", "10:00"},
+		},
+		{
+			"aligned to the quarter hour",
+			timestamp.StepRange{Start: jan31.Add(20 * time.Minute), End: jan31.Add(time.Hour), Step: timestamp.StepPeriod(quarterHour), Align: true},
+			"15:04",
+			[]string{"10:30", "10:45", "11:00"},
+		},
+		{
+			"aligned weeks start on Monday",
+			timestamp.StepRange{Start: jan31, End: jan31.AddDate(0, 0, 14), Step: timestamp.StepUnits(1, timestamp.Week), Align: true},
+			"Mon 2006-01-02",
+			[]string{"Mon 2024-02-05", "Mon 2024-02-12"},
+		},
+		{
+			"aligned reverse quarters",
+			timestamp.StepRange{Start: jan31, End: jan31.AddDate(0, 7, 0), Step: timestamp.StepUnits(1, timestamp.Quarter), Align: true, Reverse: true},
+			"2006-01-02 15:04",
+			[]string{"2024-07-01 00:00", "2024-04-01 00:00"},
+		},
+		{
+			"start after end",
+			timestamp.StepRange{Start: jan31, End: jan31.Add(-time.Hour), Step: timestamp.StepDuration(time.Minute)},
+			"15:04",
+			nil,
+		},
+	}
+
+	for _, test := range tests {
+		got := formatTimes(slices.Collect(test.r.All()), test.layout)
+		t.Logf("%s got %v", test.name, got)
+		is.Equal(got, test.expected) // Times should match
+	}
+}
+
+func TestStepRangeIterator(t *testing.T) {
+	is := is.New(t)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	next, err := timestamp.StepRange{Start: start, End: start.AddDate(1, 0, 0), Step: timestamp.StepUnits(1, timestamp.Year)}.Iterator()
+	is.NoErr(err)
+
+	count := 0
+	for _, ok := next(); ok; _, ok = next() {
+		count++
+	}
+	is.Equal(count, 2)
+	_, ok := next()
+	is.True(!ok) // Should stay finished
+
+	// Stopping a range over func loop early
+	count = 0
+	for range (timestamp.StepRange{Start: start, End: start.AddDate(1, 0, 0), Step: timestamp.StepDuration(time.Hour)}).All() {
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	is.Equal(count, 5)
+
+	for _, step := range []timestamp.Step{{}, timestamp.StepDuration(0), timestamp.StepUnits(-1, timestamp.Day), timestamp.StepPeriod(timestamp.Period{Days: 1, Clock: -48 * time.Hour})} {
+		_, err := timestamp.StepRange{Start: start, End: start.AddDate(0, 0, 5), Step: step}.Iterator()
+		is.True(err != nil) // Step should be rejected
+		t.Log(err)
+	}
+}
@@ -0,0 +1,248 @@
+package timestamp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// strftimeCache compiled strftime patterns
+var strftimeCache layoutCache
+
+// strftimeDirectives the layout field for each supported directive
+var strftimeDirectives = map[byte]layoutField{
+	'Y': {kind: fieldYear, width: 4, pad: '0'},
+	'y': {kind: fieldYear2, width: 2, pad: '0'},
+	'C': {kind: fieldCentury, width: 2, pad: '0'},
+	'G': {kind: fieldISOYear, width: 4, pad: '0'},
+	'g': {kind: fieldISOYear2, width: 2, pad: '0'},
+	'm': {kind: fieldMonth, width: 2, pad: '0'},
+	'b': {kind: fieldMonthAbbr},
+	'h': {kind: fieldMonthAbbr},
+	'B': {kind: fieldMonthName},
+	'd': {kind: fieldDay, width: 2, pad: '0'},
+	'e': {kind: fieldDay, width: 2, pad: ' '},
+	'j': {kind: fieldYearDay, width: 3, pad: '0'},
+	'a': {kind: fieldWeekdayAbbr},
+	'A': {kind: fieldWeekdayName},
+	'u': {kind: fieldWeekdayISO, width: 1, pad: '0'},
+	'w': {kind: fieldWeekday, width: 1, pad: '0'},
+	'V': {kind: fieldISOWeek, width: 2, pad: '0'},
+	'U': {kind: fieldWeekSunday, width: 2, pad: '0'},
+	'W': {kind: fieldWeekMonday, width: 2, pad: '0'},
+	'H': {kind: fieldHour, width: 2, pad: '0'},
+	'k': {kind: fieldHour, width: 2, pad: ' '},
+	'I': {kind: fieldHour12, width: 2, pad: '0'},
+	'l': {kind: fieldHour12, width: 2, pad: ' '},
+	'M': {kind: fieldMinute, width: 2, pad: '0'},
+	'S': {kind: fieldSecond, width: 2, pad: '0'},
+	'f': {kind: fieldFraction, width: 6, flags: fractionLenient},
+	'N': {kind: fieldFraction, width: 9, flags: fractionLenient},
+	'p': {kind: fieldAMPM},
+	'P': {kind: fieldAMPMLower},
+	'z': {kind: fieldOffset},
+	'Z': {kind: fieldZoneName},
+	's': {kind: fieldUnix},
+}
+
+// strftimeComposites directives that expand to other directives
+var strftimeComposites = map[byte]string{
+	'F': "%Y-%m-%d",
+	'T': "%H:%M:%S",
+	'D': "%m/%d/%y",
+	'R': "%H:%M",
+	'r': "%I:%M:%S %p",
+}
+
+// strftimeLiterals directives that write literal text
+var strftimeLiterals = map[byte]string{
+	'n': "\n",
+	't': "\t",
+	'%': "%",
+}
+
+// strftimeError make an error for a pattern problem
+func strftimeError(reason string, pattern string, pos int) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.strftime: ").S(reason).S(" '").S(pattern[pos:]).S("'").C('@').D(pos).S(" in pattern ").S(pattern)
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// appendLiteral add literal text to a layout, joining it to a preceding
+// literal
+func appendLiteral(layout []layoutField, text string) []layoutField {
+	if n := len(layout); n > 0 && layout[n-1].kind == fieldLiteral {
+		layout[n-1].text += text
+		return layout
+	}
+	return append(layout, layoutField{kind: fieldLiteral, text: text})
+}
+
+// compileStrftime compile a strftime pattern into a layout
+func compileStrftime(pattern string) (layout []layoutField, err error) {
+	if l, ok := strftimeCache.get(pattern); ok {
+		return l, nil
+	}
+
+	for i := 0; i < len(pattern); {
+		if pattern[i] != '%' {
+			j := i
+			for j < len(pattern) && pattern[j] != '%' {
+				j++
+			}
+			layout = appendLiteral(layout, pattern[i:j])
+			i = j
+			continue
+		}
+
+		start := i
+		i++
+
+		// Optional GNU flag
+		var flag byte
+		if i < len(pattern) && (pattern[i] == '-' || pattern[i] == '_' || pattern[i] == '0') {
+			flag = pattern[i]
+			i++
+		}
+		// Optional width
+		width := 0
+		for i < len(pattern) && pattern[i] >= '0' && pattern[i] <= '9' {
+			width = width*10 + int(pattern[i]-'0')
+			i++
+		}
+		// Optional colons for %:z and %::z
+		colons := 0
+		for colons < 2 && i < len(pattern) && pattern[i] == ':' {
+			colons++
+			i++
+		}
+		if i >= len(pattern) {
+			return nil, strftimeError("incomplete directive", pattern, start)
+		}
+		c := pattern[i]
+		i++
+
+		if colons > 0 && c != 'z' {
+			return nil, strftimeError("unsupported directive", pattern, start)
+		}
+
+		if text, ok := strftimeLiterals[c]; ok {
+			layout = appendLiteral(layout, text)
+			continue
+		}
+		if composite, ok := strftimeComposites[c]; ok {
+			expanded, _ := compileStrftime(composite)
+			for _, f := range expanded {
+				if f.kind == fieldLiteral {
+					layout = appendLiteral(layout, f.text)
+				} else {
+					layout = append(layout, f)
+				}
+			}
+			continue
+		}
+		f, ok := strftimeDirectives[c]
+		if !ok {
+			return nil, strftimeError("unsupported directive", pattern, start)
+		}
+
+		switch {
+		case f.kind == fieldFraction:
+			if width > 9 {
+				return nil, strftimeError("fraction width must be 1 to 9", pattern, start)
+			}
+			if width > 0 {
+				f.width = width
+			}
+		case f.kind == fieldOffset && colons == 1:
+			f.flags = offsetColon
+		case f.kind == fieldOffset && colons == 2:
+			f.flags = offsetColon | offsetSecondsAlways
+		case f.pad != 0:
+			switch flag {
+			case '-':
+				f.width = 0
+			case '_':
+				f.pad = ' '
+			case '0':
+				f.pad = '0'
+			}
+			if width > 0 {
+				f.width = width
+			}
+		}
+		layout = append(layout, f)
+	}
+
+	strftimeCache.put(pattern, layout)
+
+	return
+}
+
+// Strftime format a time using a C/Python strftime pattern. The result is in
+// the location of t.
+//
+// Supported directives:
+//
+//	%Y  year, at least 4 digits        %y  year without century 00-99
+//	%C  century 00-99                  %G  ISO-8601 week based year
+//	%g  ISO-8601 week based year 00-99 %m  month 01-12
+//	%b  abbreviated month name         %h  same as %b
+//	%B  full month name                %d  day of month 01-31
+//	%e  day of month, space padded     %j  day of year 001-366
+//	%a  abbreviated weekday name       %A  full weekday name
+//	%u  weekday 1 (Monday) to 7        %w  weekday 0 (Sunday) to 6
+//	%V  ISO-8601 week 01-53            %U  week of year, Sunday first 00-53
+//	%W  week of year, Monday first     %H  hour 00-23
+//	%k  hour, space padded             %I  hour 01-12
+//	%l  hour 1-12, space padded        %M  minute 00-59
+//	%S  second 00-59                   %f  microseconds, 6 digits
+//	%N  nanoseconds, 9 digits          %p  AM or PM
+//	%P  am or pm                       %z  offset +hhmm
+//	%:z offset +hh:mm                  %::z offset +hh:mm:ss
+//	%Z  zone abbreviation              %s  seconds since the Unix epoch
+//	%F  same as %Y-%m-%d               %T  same as %H:%M:%S
+//	%D  same as %m/%d/%y               %R  same as %H:%M
+//	%r  same as %I:%M:%S %p            %n  newline
+//	%t  tab                            %%  a literal %
+//
+// The GNU flags - (no padding), _ (space padding) and 0 (zero padding) may
+// follow the %, as in %-d. A width may be given for %f and %N to choose the
+// number of fraction digits, as in %3N for milliseconds. Any other directive,
+// including locale dependent ones such as %c and %x, is an error rather than
+// being passed through.
+func Strftime(t time.Time, pattern string) (string, error) {
+	layout, err := compileStrftime(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	return formatLayout(t, layout), nil
+}
+
+// Strptime parse a value using a C/Python strftime pattern. The supported
+// directives are those documented for Strftime. Whitespace in the pattern
+// matches one or more whitespace characters in the value, names are matched
+// without regard to case, %z accepts Z, +hh, +hhmm and +hh:mm, and %f and %N
+// accept 1 to 9 digits.
+//
+// If the value has no offset or zone location is used. A %Z zone name other
+// than UTC or GMT must be an abbreviation location uses at the parsed time,
+// matched without regard to case, even when location is UTC. Missing date
+// parts default to January 1 and a date may instead be given by day of year
+// (%Y %j), ISO-8601 week (%G %V %u) or week of year (%Y %U %w).
+// Out of range values are an error rather than being normalized.
+func Strptime(value string, pattern string, location *time.Location) (time.Time, error) {
+	layout, err := compileStrftime(pattern)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if location == nil {
+		location = time.UTC
+	}
+
+	return parseLayout("Strptime", value, layout, location)
+}
@@ -0,0 +1,137 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestStrftime(t *testing.T) {
+	is := is.New(t)
+
+	est := time.FixedZone("EST", -5*60*60)
+	ts := time.Date(2021, 1, 3, 7, 4, 5, 123456789, est)
+
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{"%Y-%m-%dT%H:%M:%S%z", "2021-01-03T07:04:05-0500"},
+		{"%Y-%m-%dT%H:%M:%S.%f%:z", "2021-01-03T07:04:05.123456-05:00"},
+		{"%H:%M %::z", "07:04 -05:00:00"},
+		{"%F %T.%3N %Z", "2021-01-03 07:04:05.123 EST"},
+		{"%j", "003"},
+		// 2021-01-03 is a Sunday in ISO week 53 of 2020
+		{"%G-W%V-%u", "2020-W53-7"},
+		{"%s", "1609675445"},
+		{"%a %A %b %B", "Sun Sunday Jan January"},
+		{"%I:%M %p %P", "07:04 AM am"},
+		{"%-d/%-m/%y|%e|%_H", "3/1/21| 3| 7"},
+		{"%U %W %w %C", "01 00 0 20"},
+		{"100%%", "100%"},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.Strftime(ts, test.pattern)
+		is.NoErr(err) // Pattern should be supported
+		t.Logf("pattern %q got %q", test.pattern, got)
+		is.Equal(got, test.expected)
+	}
+
+	for _, pattern := range []string{"%c", "%x", "%Q", "%", "%:H", "%10N"} {
+		_, err := timestamp.Strftime(ts, pattern)
+		t.Logf("pattern %q error %v", pattern, err)
+		is.True(err != nil) // Unsupported directives should be an error
+	}
+}
+
+func TestStrptime(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err) // Location should load
+
+	tests := []struct {
+		value    string
+		pattern  string
+		expected time.Time
+	}{
+		{"2021-01-03T07:04:05-0500", "%Y-%m-%dT%H:%M:%S%z",
+			time.Date(2021, 1, 3, 12, 4, 5, 0, time.UTC)},
+		{"2021-01-03T07:04:05.5+05:30", "%Y-%m-%dT%H:%M:%S.%f%z",
+			time.Date(2021, 1, 3, 1, 34, 5, 500000000, time.UTC)},
+		{"2021-01-03 07:04:05Z", "%Y-%m-%d %H:%M:%S%z",
+			time.Date(2021, 1, 3, 7, 4, 5, 0, time.UTC)},
+		{"2021-01-03   07:04", "%Y-%m-%d %H:%M",
+			time.Date(2021, 1, 3, 7, 4, 0, 0, toronto)},
+		{"2020-W53-7", "%G-W%V-%u", time.Date(2021, 1, 3, 0, 0, 0, 0, toronto)},
+		{"2024-060", "%Y-%j", time.Date(2024, 2, 29, 0, 0, 0, 0, toronto)},
+		{"1609675445", "%s", time.Date(2021, 1, 3, 12, 4, 5, 0, time.UTC)},
+		{"sunday, 3 JANUARY 21 7:04 pm", "%A, %d %B %y %I:%M %p",
+			time.Date(2021, 1, 3, 19, 4, 0, 0, toronto)},
+		{"20210703 EDT", "%Y%m%d %Z", time.Date(2021, 7, 3, 0, 0, 0, 0, toronto)},
+		{"03/Jan/2021:07:04:05 +0000", "%d/%b/%Y:%H:%M:%S %z",
+			time.Date(2021, 1, 3, 7, 4, 5, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.Strptime(test.value, test.pattern, toronto)
+		is.NoErr(err) // Value should parse
+		t.Logf("value %q pattern %q got %v", test.value, test.pattern, got)
+		is.True(got.Equal(test.expected)) // Result should match expected
+	}
+
+	bad := []struct {
+		value   string
+		pattern string
+	}{
+		{"2021-02-30", "%Y-%m-%d"},
+		{"2021-01-03 25:00", "%Y-%m-%d %H:%M"},
+		{"2021-01-03x", "%Y-%m-%d"},
+		{"2021-01-03 PST", "%Y-%m-%d %Z"},
+		{"2023-366", "%Y-%j"},
+		{"2021-01-03", "%Y-%m-%d %c"},
+	}
+	for _, test := range bad {
+		_, err := timestamp.Strptime(test.value, test.pattern, toronto)
+		t.Logf("value %q pattern %q error %v", test.value, test.pattern, err)
+		is.True(err != nil) // Should be an error
+	}
+
+	// A zone name must be one the location uses, even for UTC
+	_, err = timestamp.Strptime("2024-01-02 03:04:05 EST", "%Y-%m-%d %H:%M:%S %Z", time.UTC)
+	t.Log(err)
+	is.True(err != nil) // EST is not used by UTC
+	got, err := timestamp.Strptime("2024-01-02 03:04:05 gmt", "%Y-%m-%d %H:%M:%S %Z", toronto)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)))
+	got, err = timestamp.Strptime("2024-01-02 03:04:05 est", "%Y-%m-%d %H:%M:%S %Z", toronto)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, toronto)))
+
+	// Round trip
+	ts := time.Date(2021, 7, 3, 7, 4, 5, 123456000, toronto)
+	pattern := "%Y-%m-%dT%H:%M:%S.%f%:z"
+	s, err := timestamp.Strftime(ts, pattern)
+	is.NoErr(err)
+	back, err := timestamp.Strptime(s, pattern, time.UTC)
+	is.NoErr(err)
+	is.True(back.Equal(ts)) // Round trip should give the same instant
+}
+
+func BenchmarkStrftime(b *testing.B) {
+	is := is.New(b)
+
+	ts := time.Date(2021, 1, 3, 7, 4, 5, 123456789, time.UTC)
+	var s string
+	var err error
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s, err = timestamp.Strftime(ts, "%Y-%m-%dT%H:%M:%S.%f%z")
+	}
+	is.NoErr(err)
+	is.True(s != "")
+}
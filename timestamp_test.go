@@ -676,6 +676,59 @@ func TestRangeOverTimes(t *testing.T) {
 	}
 }
 
+// Day ranges use the location of the start and calendar days in it
+func TestRangeOverDays(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	// Offsets differ between January and July but the location is the same
+	count := 0
+	for rd := timestamp.RangeOverTimes(time.Date(2024, 1, 15, 12, 0, 0, 0, toronto), time.Date(2024, 7, 15, 9, 0, 0, 0, toronto)); ; {
+		day, err := rd()
+		is.NoErr(err) // The same location should not be an error
+		if day.IsZero() {
+			break
+		}
+		is.Equal(day.Hour(), 0) // Each day should start at midnight
+		count++
+	}
+	is.Equal(count, 183)
+
+	// A different location is an error unless the end is converted
+	start := time.Date(2024, 3, 9, 12, 0, 0, 0, toronto)
+	end := time.Date(2024, 3, 12, 2, 0, 0, 0, time.UTC)
+	_, err = timestamp.RangeOverTimes(start, end)()
+	is.True(err != nil)
+
+	var days []string
+	for rd := timestamp.RangeOverDays(start, end, true); ; {
+		day, err := rd()
+		is.NoErr(err)
+		if day.IsZero() {
+			break
+		}
+		days = append(days, day.Format(time.RFC3339))
+	}
+	// 02:00 UTC on the 12th is the 11th in Toronto
+	is.Equal(days, []string{"2024-03-09T00:00:00-05:00", "2024-03-10T00:00:00-05:00", "2024-03-11T00:00:00-04:00"})
+
+	// Midnight is skipped in Santiago on 2022-09-11
+	santiago, err := time.LoadLocation("America/Santiago")
+	is.NoErr(err)
+	days = nil
+	for rd := timestamp.RangeOverTimes(time.Date(2022, 9, 10, 8, 0, 0, 0, santiago), time.Date(2022, 9, 12, 8, 0, 0, 0, santiago)); ; {
+		day, err := rd()
+		is.NoErr(err)
+		if day.IsZero() {
+			break
+		}
+		days = append(days, day.Format(time.RFC3339))
+	}
+	is.Equal(days, []string{"2022-09-10T00:00:00-04:00", "2022-09-11T01:00:00-03:00", "2022-09-12T00:00:00-03:00"})
+}
+
 // TestOrdering check ordering call
 func TestOrdering(t *testing.T) {
 	is := is.New(t)
@@ -1377,3 +1430,67 @@ func BenchmarkRunesToStringCast(b *testing.B) {
 
 	is.True(s != "")
 }
+
+// No overflow reports false as well as giving the sum
+func TestOverflows(t *testing.T) {
+	is := is.New(t)
+
+	sum, overflows := timestamp.DurationOverflows(time.Hour, time.Minute)
+	is.True(!overflows)                          // Should not overflow
+	is.Equal(time.Duration(sum), 61*time.Minute) // Sum should match
+
+	_, overflows = timestamp.DurationOverflows(1<<63-1, time.Nanosecond)
+	is.True(overflows) // Should overflow
+
+	_, overflows = timestamp.DurationOverflows()
+	is.True(!overflows) // Nothing should not overflow
+
+	isum, overflows := timestamp.Int64Overflows(40, 2)
+	is.True(!overflows) // Should not overflow
+	is.Equal(isum, int64(42))
+
+	_, overflows = timestamp.Int64Overflows(-1<<63, -1)
+	is.True(overflows) // Should overflow
+}
+
+// Parsing cases that once went wrong: a GMT layout in a location other than
+// UTC, Unix timestamps with more than 10 digits and US style dates
+func TestParseLayouts(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	// GMT is literal text to Go so the location must not be used
+	got, err := timestamp.ParseInLocation("Mon, 02 Jan 2006 15:04:05 GMT", toronto)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)))
+
+	// Digits past the seconds are a fraction without a period
+	tests := []struct {
+		value    string
+		expected time.Time
+	}{
+		{"1136214245123", time.Date(2006, 1, 2, 15, 4, 5, 123000000, time.UTC)},
+		{"1136214245123456", time.Date(2006, 1, 2, 15, 4, 5, 123456000, time.UTC)},
+		{"1136214245123456789", time.Date(2006, 1, 2, 15, 4, 5, 123456789, time.UTC)},
+	}
+	for _, test := range tests {
+		got, err := timestamp.ParseUnixTS(test.value)
+		is.NoErr(err)
+		is.True(got.Equal(test.expected)) // Unix timestamp should keep all digits
+		got, err = timestamp.ParseInLocation(test.value, toronto)
+		is.NoErr(err)
+		is.True(got.Equal(test.expected)) // Unix timestamp should keep all digits
+	}
+
+	// US style dates reach the month/day/year layouts in the location
+	for _, value := range []string{"01/02/2006", "1/2/2006"} {
+		got, err := timestamp.ParseInLocation(value, toronto)
+		is.NoErr(err)
+		is.True(got.Equal(time.Date(2006, 1, 2, 0, 0, 0, 0, toronto)))
+	}
+	got, err = timestamp.ParseInLocation("12/31/2021", toronto)
+	is.NoErr(err)
+	is.True(got.Equal(time.Date(2021, 12, 31, 0, 0, 0, 0, toronto)))
+}
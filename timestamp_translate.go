@@ -0,0 +1,518 @@
+package timestamp
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// Dialect a syntax for describing timestamp layouts
+type Dialect int
+
+// Supported layout dialects
+const (
+	DialectGo       Dialect = iota // Go reference layouts such as 2006-01-02T15:04:05Z07:00
+	DialectStrftime                // C/Python strftime patterns such as %Y-%m-%dT%H:%M:%S%z
+	DialectLDML                    // Unicode LDML/Java patterns such as yyyy-MM-dd'T'HH:mm:ssXXX
+)
+
+// String get the name of the dialect
+func (d Dialect) String() string {
+	switch d {
+	case DialectGo:
+		return "Go"
+	case DialectStrftime:
+		return "strftime"
+	case DialectLDML:
+		return "LDML"
+	}
+	return "unknown"
+}
+
+// goLayoutCache compiled Go layouts
+var goLayoutCache layoutCache
+
+// startsWithLowerCase is the first byte of s a lower case letter. Go layouts
+// don't treat Jan or Mon as a chunk when it is followed by one.
+func startsWithLowerCase(s string) bool {
+	return len(s) > 0 && s[0] >= 'a' && s[0] <= 'z'
+}
+
+// nextGoChunk find the first reference value in a Go layout. The text before
+// it, the field and the text after it are returned. If there are no more
+// reference values found is false. This follows the rules of Go's time
+// package.
+func nextGoChunk(layout string) (prefix string, f layoutField, suffix string, found bool) {
+	for i := 0; i < len(layout); i++ {
+		rest := layout[i:]
+		chunk := func(n int, field layoutField) (string, layoutField, string, bool) {
+			return layout[:i], field, layout[i+n:], true
+		}
+
+		switch c := layout[i]; c {
+		case 'J':
+			if strings.HasPrefix(rest, "January") {
+				return chunk(7, layoutField{kind: fieldMonthName})
+			}
+			if strings.HasPrefix(rest, "Jan") && !startsWithLowerCase(rest[3:]) {
+				return chunk(3, layoutField{kind: fieldMonthAbbr})
+			}
+		case 'M':
+			if strings.HasPrefix(rest, "Monday") {
+				return chunk(6, layoutField{kind: fieldWeekdayName})
+			}
+			if strings.HasPrefix(rest, "Mon") && !startsWithLowerCase(rest[3:]) {
+				return chunk(3, layoutField{kind: fieldWeekdayAbbr})
+			}
+			if strings.HasPrefix(rest, "MST") {
+				return chunk(3, layoutField{kind: fieldZoneName})
+			}
+		case '0':
+			if len(rest) >= 2 && rest[1] >= '1' && rest[1] <= '6' {
+				kinds := [...]fieldKind{fieldMonth, fieldDay, fieldHour12, fieldMinute, fieldSecond, fieldYear2}
+				return chunk(2, layoutField{kind: kinds[rest[1]-'1'], width: 2, pad: '0'})
+			}
+			if strings.HasPrefix(rest, "002") {
+				return chunk(3, layoutField{kind: fieldYearDay, width: 3, pad: '0'})
+			}
+		case '1':
+			if strings.HasPrefix(rest, "15") {
+				return chunk(2, layoutField{kind: fieldHour, width: 2, pad: '0'})
+			}
+			return chunk(1, layoutField{kind: fieldMonth})
+		case '2':
+			if strings.HasPrefix(rest, "2006") {
+				return chunk(4, layoutField{kind: fieldYear, width: 4, pad: '0'})
+			}
+			return chunk(1, layoutField{kind: fieldDay})
+		case '_':
+			if strings.HasPrefix(rest, "_2") {
+				// _2006 is a literal _ followed by a year
+				if strings.HasPrefix(rest, "_2006") {
+					return layout[:i+1], layoutField{kind: fieldYear, width: 4, pad: '0'}, layout[i+5:], true
+				}
+				return chunk(2, layoutField{kind: fieldDay, width: 2, pad: ' '})
+			}
+			if strings.HasPrefix(rest, "__2") {
+				return chunk(3, layoutField{kind: fieldYearDay, width: 3, pad: ' '})
+			}
+		case '3':
+			return chunk(1, layoutField{kind: fieldHour12})
+		case '4':
+			return chunk(1, layoutField{kind: fieldMinute})
+		case '5':
+			return chunk(1, layoutField{kind: fieldSecond})
+		case 'P':
+			if strings.HasPrefix(rest, "PM") {
+				return chunk(2, layoutField{kind: fieldAMPM})
+			}
+		case 'p':
+			if strings.HasPrefix(rest, "pm") {
+				return chunk(2, layoutField{kind: fieldAMPMLower})
+			}
+		case '-', 'Z':
+			flags := 0
+			if c == 'Z' {
+				flags = offsetZ
+			}
+			switch {
+			case strings.HasPrefix(rest[1:], "070000"):
+				return chunk(7, layoutField{kind: fieldOffset, flags: flags | offsetSecondsAlways})
+			case strings.HasPrefix(rest[1:], "07:00:00"):
+				return chunk(9, layoutField{kind: fieldOffset, flags: flags | offsetColon | offsetSecondsAlways})
+			case strings.HasPrefix(rest[1:], "0700"):
+				return chunk(5, layoutField{kind: fieldOffset, flags: flags})
+			case strings.HasPrefix(rest[1:], "07:00"):
+				return chunk(6, layoutField{kind: fieldOffset, flags: flags | offsetColon})
+			case strings.HasPrefix(rest[1:], "07"):
+				return chunk(3, layoutField{kind: fieldOffset, flags: flags | offsetNoMinutes})
+			}
+		case '.', ',':
+			// A run of 0s or 9s after a separator that is not followed by
+			// another digit is a fraction of a second
+			if len(rest) >= 2 && (rest[1] == '0' || rest[1] == '9') {
+				ch := rest[1]
+				j := 1
+				for j < len(rest) && rest[j] == ch {
+					j++
+				}
+				if j < len(rest) && rest[j] >= '0' && rest[j] <= '9' {
+					break
+				}
+				if ch == '0' {
+					// The separator is kept as a literal
+					return layout[:i+1], layoutField{kind: fieldFraction, width: j - 1}, layout[i+j:], true
+				}
+				return chunk(j, layoutField{kind: fieldFraction, width: j - 1, flags: fractionTrim, text: rest[:1]})
+			}
+		}
+	}
+
+	return layout, layoutField{}, "", false
+}
+
+// compileGoLayout compile a Go reference layout into a layout
+func compileGoLayout(goLayout string) (layout []layoutField) {
+	if l, ok := goLayoutCache.get(goLayout); ok {
+		return l
+	}
+
+	rest := goLayout
+	for rest != "" {
+		prefix, f, suffix, found := nextGoChunk(rest)
+		if prefix != "" {
+			layout = appendLiteral(layout, prefix)
+		}
+		if !found {
+			break
+		}
+		layout = append(layout, f)
+		rest = suffix
+	}
+
+	goLayoutCache.put(goLayout, layout)
+
+	return
+}
+
+// compileDialect compile a pattern in any dialect
+func compileDialect(pattern string, dialect Dialect) ([]layoutField, error) {
+	switch dialect {
+	case DialectGo:
+		return compileGoLayout(pattern), nil
+	case DialectStrftime:
+		return compileStrftime(pattern)
+	case DialectLDML:
+		return compileLDML(pattern)
+	}
+	return nil, errors.New("timestamp.TranslateLayout: unknown dialect")
+}
+
+// padding classes for numeric fields
+const (
+	padNone  = iota // no padding
+	padZero         // zero padded to the natural width
+	padSpace        // space padded to the natural width
+	padOther        // padded to an unusual width
+)
+
+// naturalWidths the usual padded width of numeric fields
+var naturalWidths = map[fieldKind]int{
+	fieldYear: 4, fieldYear2: 2, fieldCentury: 2, fieldISOYear: 4, fieldISOYear2: 2,
+	fieldMonth: 2, fieldDay: 2, fieldYearDay: 3, fieldWeekdayISO: 1, fieldWeekday: 1,
+	fieldISOWeek: 2, fieldWeekSunday: 2, fieldWeekMonday: 2,
+	fieldHour: 2, fieldHour12: 2, fieldMinute: 2, fieldSecond: 2,
+}
+
+// padding classify the padding of a numeric field
+func (f layoutField) padding() int {
+	switch {
+	case f.width <= 1:
+		return padNone
+	case f.width != naturalWidths[f.kind]:
+		return padOther
+	case f.pad == ' ':
+		return padSpace
+	}
+	return padZero
+}
+
+// goChunks the Go reference value for fields that have one, keyed by kind
+// and padding class (none, zero, space)
+var goChunks = map[fieldKind][3]string{
+	fieldYear:        {"", "2006", ""},
+	fieldYear2:       {"", "06", ""},
+	fieldMonth:       {"1", "01", ""},
+	fieldDay:         {"2", "02", "_2"},
+	fieldYearDay:     {"", "002", "__2"},
+	fieldHour:        {"", "15", ""},
+	fieldHour12:      {"3", "03", ""},
+	fieldMinute:      {"4", "04", ""},
+	fieldSecond:      {"5", "05", ""},
+	fieldMonthAbbr:   {"Jan", "Jan", "Jan"},
+	fieldMonthName:   {"January", "January", "January"},
+	fieldWeekdayAbbr: {"Mon", "Mon", "Mon"},
+	fieldWeekdayName: {"Monday", "Monday", "Monday"},
+	fieldAMPM:        {"PM", "PM", "PM"},
+	fieldAMPMLower:   {"pm", "pm", "pm"},
+	fieldZoneName:    {"MST", "MST", "MST"},
+}
+
+// strftimeChunks the strftime directive for fields that have one, keyed by
+// kind and padding class (none, zero, space)
+var strftimeChunks = map[fieldKind][3]string{
+	fieldYear:        {"%-Y", "%Y", "%_Y"},
+	fieldYear2:       {"%-y", "%y", "%_y"},
+	fieldCentury:     {"%-C", "%C", "%_C"},
+	fieldISOYear:     {"%-G", "%G", "%_G"},
+	fieldISOYear2:    {"%-g", "%g", "%_g"},
+	fieldMonth:       {"%-m", "%m", "%_m"},
+	fieldDay:         {"%-d", "%d", "%e"},
+	fieldYearDay:     {"%-j", "%j", "%_j"},
+	fieldWeekdayISO:  {"%u", "%u", "%u"},
+	fieldWeekday:     {"%w", "%w", "%w"},
+	fieldISOWeek:     {"%-V", "%V", "%_V"},
+	fieldWeekSunday:  {"%-U", "%U", "%_U"},
+	fieldWeekMonday:  {"%-W", "%W", "%_W"},
+	fieldHour:        {"%-H", "%H", "%k"},
+	fieldHour12:      {"%-I", "%I", "%l"},
+	fieldMinute:      {"%-M", "%M", "%_M"},
+	fieldSecond:      {"%-S", "%S", "%_S"},
+	fieldMonthAbbr:   {"%b", "%b", "%b"},
+	fieldMonthName:   {"%B", "%B", "%B"},
+	fieldWeekdayAbbr: {"%a", "%a", "%a"},
+	fieldWeekdayName: {"%A", "%A", "%A"},
+	fieldAMPM:        {"%p", "%p", "%p"},
+	fieldAMPMLower:   {"%P", "%P", "%P"},
+	fieldZoneName:    {"%Z", "%Z", "%Z"},
+	fieldUnix:        {"%s", "%s", "%s"},
+}
+
+// ldmlChunks the LDML letters for fields that have one, keyed by kind and
+// padding class (none, zero, space)
+var ldmlChunks = map[fieldKind][3]string{
+	fieldYear:        {"y", "yyyy", ""},
+	fieldYear2:       {"", "yy", ""},
+	fieldISOYear:     {"Y", "YYYY", ""},
+	fieldISOYear2:    {"", "YY", ""},
+	fieldMonth:       {"M", "MM", ""},
+	fieldDay:         {"d", "dd", ""},
+	fieldYearDay:     {"D", "DDD", ""},
+	fieldWeekdayISO:  {"e", "e", ""},
+	fieldISOWeek:     {"w", "ww", ""},
+	fieldHour:        {"H", "HH", ""},
+	fieldHour12:      {"h", "hh", ""},
+	fieldMinute:      {"m", "mm", ""},
+	fieldSecond:      {"s", "ss", ""},
+	fieldMonthAbbr:   {"MMM", "MMM", "MMM"},
+	fieldMonthName:   {"MMMM", "MMMM", "MMMM"},
+	fieldWeekdayAbbr: {"EEE", "EEE", "EEE"},
+	fieldWeekdayName: {"EEEE", "EEEE", "EEEE"},
+	fieldAMPM:        {"a", "a", "a"},
+	fieldZoneName:    {"z", "z", "z"},
+}
+
+// goOffsets, strftimeOffsets and ldmlOffsets the offset syntax keyed by
+// offset flags
+var goOffsets = map[int]string{
+	0:                                 "-0700",
+	offsetColon:                       "-07:00",
+	offsetNoMinutes:                   "-07",
+	offsetSecondsAlways:               "-070000",
+	offsetColon | offsetSecondsAlways: "-07:00:00",
+	offsetZ:                           "Z0700",
+	offsetZ | offsetColon:             "Z07:00",
+	offsetZ | offsetNoMinutes:         "Z07",
+	offsetZ | offsetSecondsAlways:     "Z070000",
+	offsetZ | offsetColon | offsetSecondsAlways: "Z07:00:00",
+}
+
+var strftimeOffsets = map[int]string{
+	0:                                 "%z",
+	offsetColon:                       "%:z",
+	offsetColon | offsetSecondsAlways: "%::z",
+}
+
+var ldmlOffsets = map[int]string{
+	0:                                     "xx",
+	offsetColon:                           "xxx",
+	offsetHours:                           "x",
+	offsetSeconds:                         "xxxx",
+	offsetColon | offsetSeconds:           "xxxxx",
+	offsetZ:                               "XX",
+	offsetZ | offsetColon:                 "XXX",
+	offsetZ | offsetHours:                 "X",
+	offsetZ | offsetSeconds:               "XXXX",
+	offsetZ | offsetColon | offsetSeconds: "XXXXX",
+}
+
+// translateError make an error for a field with no equivalent in a dialect
+func translateError(reason string, pattern string, from Dialect, to Dialect) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S("timestamp.TranslateLayout: ").S(reason).S(" in ").S(from.String()).S(" pattern ").Q(pattern).
+		S(" has no ").S(to.String()).S(" equivalent")
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// fieldDescription a short description of a field for error messages
+func fieldDescription(f layoutField) string {
+	names := [...]string{
+		fieldLiteral: "literal", fieldYear: "year", fieldYear2: "two digit year", fieldCentury: "century",
+		fieldISOYear: "ISO week year", fieldISOYear2: "two digit ISO week year", fieldMonth: "month",
+		fieldMonthAbbr: "month abbreviation", fieldMonthName: "month name", fieldDay: "day",
+		fieldYearDay: "day of year", fieldWeekdayAbbr: "weekday abbreviation",
+		fieldWeekdayName: "weekday name", fieldWeekdayISO: "ISO weekday number",
+		fieldWeekday: "weekday number", fieldISOWeek: "ISO week", fieldWeekSunday: "Sunday based week",
+		fieldWeekMonday: "Monday based week", fieldHour: "hour", fieldHour12: "12 hour clock hour",
+		fieldMinute: "minute", fieldSecond: "second", fieldFraction: "fraction of a second",
+		fieldAMPM: "AM/PM", fieldAMPMLower: "am/pm", fieldOffset: "UTC offset", fieldZoneName: "zone name",
+		fieldUnix: "Unix seconds",
+	}
+	name := names[f.kind]
+	if f.kind != fieldLiteral && naturalWidths[f.kind] > 1 {
+		switch f.padding() {
+		case padNone:
+			name = "unpadded " + name
+		case padSpace:
+			name = "space padded " + name
+		case padOther:
+			name = "odd width " + name
+		}
+	}
+	return name
+}
+
+// TranslateLayout translate a layout pattern from one dialect to another. Go
+// reference layouts like those in nonISOTimeFormats, strftime patterns and
+// Unicode LDML patterns are supported.
+//
+// Translation is exact. A component that has no equivalent in the target
+// dialect, such as a space padded day in LDML or a week of year in a Go
+// layout, is an error that names the component. Go layouts have no way to
+// quote literal text, so literal text that Go would read as a reference value
+// is also an error.
+func TranslateLayout(pattern string, from Dialect, to Dialect) (string, error) {
+	layout, err := compileDialect(pattern, from)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, f := range layout {
+		if f.kind == fieldLiteral {
+			writeLiteral(&b, f.text, to)
+			continue
+		}
+
+		chunk := ""
+		switch {
+		case f.kind == fieldOffset:
+			switch to {
+			case DialectGo:
+				chunk = goOffsets[f.flags]
+			case DialectStrftime:
+				chunk = strftimeOffsets[f.flags]
+			case DialectLDML:
+				chunk = ldmlOffsets[f.flags]
+			}
+		case f.kind == fieldFraction:
+			chunk = fractionChunk(f, layout, i, to)
+		default:
+			padding := f.padding()
+			if padding != padOther {
+				switch to {
+				case DialectGo:
+					chunk = goChunks[f.kind][padding]
+				case DialectStrftime:
+					chunk = strftimeChunks[f.kind][padding]
+				case DialectLDML:
+					chunk = ldmlChunks[f.kind][padding]
+				}
+			}
+		}
+		if chunk == "" {
+			return "", translateError(fieldDescription(f), pattern, from, to)
+		}
+		b.WriteString(chunk)
+	}
+
+	result := b.String()
+
+	// Go layouts can't quote literals so check the result means the same
+	// thing as the source
+	if to == DialectGo && !sameLayout(compileGoLayout(result), layout) {
+		return "", translateError("literal text", pattern, from, to)
+	}
+
+	return result, nil
+}
+
+// fractionChunk get the syntax for a fraction of a second
+func fractionChunk(f layoutField, layout []layoutField, i int, to Dialect) string {
+	if f.flags&fractionTrim != 0 {
+		// Only Go can trim trailing zeros
+		if to != DialectGo {
+			return ""
+		}
+		return f.text + strings.Repeat("9", f.width)
+	}
+	switch to {
+	case DialectGo:
+		// Go needs a . or , immediately before the digits
+		if i == 0 || layout[i-1].kind != fieldLiteral {
+			return ""
+		}
+		if prev := layout[i-1].text; !strings.HasSuffix(prev, ".") && !strings.HasSuffix(prev, ",") {
+			return ""
+		}
+		return strings.Repeat("0", f.width)
+	case DialectStrftime:
+		switch f.width {
+		case 6:
+			return "%f"
+		case 9:
+			return "%N"
+		}
+		return "%" + string(rune('0'+f.width)) + "N"
+	case DialectLDML:
+		return strings.Repeat("S", f.width)
+	}
+	return ""
+}
+
+// writeLiteral write literal text escaped for a dialect
+func writeLiteral(b *strings.Builder, text string, to Dialect) {
+	switch to {
+	case DialectStrftime:
+		b.WriteString(strings.ReplaceAll(text, "%", "%%"))
+	case DialectLDML:
+		// Quote runs of letters and double any quotes
+		for i := 0; i < len(text); {
+			switch {
+			case text[i] == '\'':
+				b.WriteString("''")
+				i++
+			case isLDMLLetter(text[i]):
+				j := i
+				for j < len(text) && isLDMLLetter(text[j]) {
+					j++
+				}
+				b.WriteByte('\'')
+				b.WriteString(text[i:j])
+				b.WriteByte('\'')
+				i = j
+			default:
+				b.WriteByte(text[i])
+				i++
+			}
+		}
+	default:
+		b.WriteString(text)
+	}
+}
+
+// sameLayout do two layouts describe the same output. Padding characters of
+// unpadded fields and parse leniency are ignored.
+func sameLayout(a, b []layoutField) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		fa, fb := a[i], b[i]
+		fa.flags &^= fractionLenient
+		fb.flags &^= fractionLenient
+		if fa.width <= 1 {
+			fa.pad = 0
+		}
+		if fb.width <= 1 {
+			fb.pad = 0
+		}
+		if fa != fb {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,116 @@
+package timestamp_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestTranslateLayout(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		pattern  string
+		from     timestamp.Dialect
+		to       timestamp.Dialect
+		expected string
+	}{
+		{"2006-01-02T15:04:05-07:00", timestamp.DialectGo, timestamp.DialectStrftime, "%Y-%m-%dT%H:%M:%S%:z"},
+		{time.RFC3339, timestamp.DialectGo, timestamp.DialectLDML, "yyyy-MM-dd'T'HH:mm:ssXXX"},
+		{time.RFC1123Z, timestamp.DialectGo, timestamp.DialectStrftime, "%a, %d %b %Y %H:%M:%S %z"},
+		{time.RFC1123Z, timestamp.DialectGo, timestamp.DialectLDML, "EEE, dd MMM yyyy HH:mm:ss xx"},
+		{time.Kitchen, timestamp.DialectGo, timestamp.DialectStrftime, "%-I:%M%p"},
+		{time.Kitchen, timestamp.DialectGo, timestamp.DialectLDML, "h:mma"},
+		{time.ANSIC, timestamp.DialectGo, timestamp.DialectStrftime, "%a %b %e %H:%M:%S %Y"},
+		{"2006-01-02 15:04:05.000", timestamp.DialectGo, timestamp.DialectLDML, "yyyy-MM-dd HH:mm:ss.SSS"},
+		{"2006-01-02 15:04:05.000000", timestamp.DialectGo, timestamp.DialectStrftime, "%Y-%m-%d %H:%M:%S.%f"},
+		{"20060102T150405-0700", timestamp.DialectGo, timestamp.DialectStrftime, "%Y%m%dT%H%M%S%z"},
+		{"2006-002 at 100%", timestamp.DialectGo, timestamp.DialectStrftime, "%Y-%j at %-m00%%"},
+		{"%Y-%m-%dT%H:%M:%S%z", timestamp.DialectStrftime, timestamp.DialectGo, "2006-01-02T15:04:05-0700"},
+		{"%F %T.%3N %Z", timestamp.DialectStrftime, timestamp.DialectGo, "2006-01-02 15:04:05.000 MST"},
+		{"%A, %B %-d %Y", timestamp.DialectStrftime, timestamp.DialectLDML, "EEEE, MMMM d yyyy"},
+		{"%::z", timestamp.DialectStrftime, timestamp.DialectGo, "-07:00:00"},
+		{"yyyy-MM-dd'T'HH:mm:ss.SSSXXX", timestamp.DialectLDML, timestamp.DialectGo, "2006-01-02T15:04:05.000Z07:00"},
+		{"dd/MM/yy h 'o''clock' a", timestamp.DialectLDML, timestamp.DialectStrftime, "%d/%m/%y %-I o'clock %p"},
+		{"YYYY-'W'ww-e", timestamp.DialectLDML, timestamp.DialectStrftime, "%G-W%V-%u"},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.TranslateLayout(test.pattern, test.from, test.to)
+		is.NoErr(err) // Pattern should translate
+		t.Logf("%s %q to %s got %q", test.from, test.pattern, test.to, got)
+		is.Equal(got, test.expected) // Translation should match
+	}
+}
+
+func TestTranslateLayoutRoundTrip(t *testing.T) {
+	is := is.New(t)
+
+	ts := time.Date(2021, 1, 3, 7, 4, 5, 123456789, time.FixedZone("IST", 5*60*60+30*60))
+	layouts := []string{
+		time.RFC3339, time.RFC3339Nano, time.RFC1123Z, time.RFC822Z, time.Kitchen, time.ANSIC,
+		time.Stamp, time.StampMilli, "2006-01-02T15:04:05.999Z07:00", "Monday, January 2 2006 3:04pm -07",
+	}
+
+	for _, layout := range layouts {
+		for _, to := range []timestamp.Dialect{timestamp.DialectStrftime, timestamp.DialectLDML} {
+			translated, err := timestamp.TranslateLayout(layout, timestamp.DialectGo, to)
+			if err != nil {
+				// Not every layout has an equivalent in every dialect
+				t.Logf("%q to %s: %v", layout, to, err)
+				continue
+			}
+			var got string
+			if to == timestamp.DialectStrftime {
+				got, err = timestamp.Strftime(ts, translated)
+			} else {
+				got, err = timestamp.FormatLDML(ts, translated)
+			}
+			is.NoErr(err)                    // Translated pattern should format
+			is.Equal(got, ts.Format(layout)) // Output should match time.Format
+			back, err := timestamp.TranslateLayout(translated, to, timestamp.DialectGo)
+			is.NoErr(err)                  // Translation back should work
+			is.Equal(ts.Format(back), got) // Translating back should give the same output
+		}
+	}
+}
+
+func TestTranslateLayoutErrors(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		pattern string
+		from    timestamp.Dialect
+		to      timestamp.Dialect
+		reason  string
+	}{
+		{"%V", timestamp.DialectStrftime, timestamp.DialectGo, "ISO week"},
+		{"%s", timestamp.DialectStrftime, timestamp.DialectLDML, "Unix seconds"},
+		{"%k", timestamp.DialectStrftime, timestamp.DialectLDML, "space padded hour"},
+		{"%-H", timestamp.DialectStrftime, timestamp.DialectGo, "unpadded hour"},
+		{"%z", timestamp.DialectStrftime, timestamp.DialectLDML, ""},
+		{"2006-01-02T15:04:05.999Z07:00", timestamp.DialectGo, timestamp.DialectStrftime, "fraction"},
+		{"Z07:00", timestamp.DialectGo, timestamp.DialectStrftime, "UTC offset"},
+		{"_2 Jan", timestamp.DialectGo, timestamp.DialectLDML, "space padded day"},
+		{"yyyy 'Monday'", timestamp.DialectLDML, timestamp.DialectGo, "literal text"},
+		{"'at' h", timestamp.DialectLDML, timestamp.DialectGo, ""},
+		{"HH:mm 'at 1'", timestamp.DialectLDML, timestamp.DialectGo, "literal text"},
+		{"%Q", timestamp.DialectStrftime, timestamp.DialectGo, "unsupported directive"},
+		{"yyyy qq", timestamp.DialectLDML, timestamp.DialectGo, "unsupported pattern letters"},
+	}
+
+	for _, test := range tests {
+		got, err := timestamp.TranslateLayout(test.pattern, test.from, test.to)
+		if test.reason == "" {
+			is.NoErr(err) // Pattern should translate
+			t.Logf("%q got %q", test.pattern, got)
+			continue
+		}
+		is.True(err != nil) // Pattern should not translate
+		t.Logf("%q error %v", test.pattern, err)
+		is.True(strings.Contains(err.Error(), test.reason)) // Error should name the problem
+	}
+}
@@ -0,0 +1,253 @@
+package timestamp
+
+import (
+	"strings"
+	"time"
+
+	"github.com/JohnCGriffin/overflow"
+	"github.com/imarsman/timestamp/pkg/utility"
+)
+
+// Unit a unit of time. Units from Nanosecond through Hour are exact
+// durations. Units from Day upward are calendar units whose length depends on
+// the date and location they are applied in.
+type Unit int
+
+// Units of time, ordered from smallest to largest.
+const (
+	Nanosecond Unit = iota + 1
+	Microsecond
+	Millisecond
+	Second
+	Minute
+	Hour
+	Day
+	Week
+	Month
+	Quarter
+	Year
+)
+
+var unitNames = [...]string{
+	Nanosecond:  "nanosecond",
+	Microsecond: "microsecond",
+	Millisecond: "millisecond",
+	Second:      "second",
+	Minute:      "minute",
+	Hour:        "hour",
+	Day:         "day",
+	Week:        "week",
+	Month:       "month",
+	Quarter:     "quarter",
+	Year:        "year",
+}
+
+// String get the singular lower case name for the unit
+func (u Unit) String() string {
+	if u < Nanosecond || u > Year {
+		return "unknown"
+	}
+	return unitNames[u]
+}
+
+// IsCalendar is the unit a calendar unit rather than an exact duration
+func (u Unit) IsCalendar() bool {
+	return u >= Day
+}
+
+// Duration get the exact duration of a unit. Calendar units have no exact
+// duration and return 0.
+func (u Unit) Duration() time.Duration {
+	switch u {
+	case Nanosecond:
+		return time.Nanosecond
+	case Microsecond:
+		return time.Microsecond
+	case Millisecond:
+		return time.Millisecond
+	case Second:
+		return time.Second
+	case Minute:
+		return time.Minute
+	case Hour:
+		return time.Hour
+	}
+	return 0
+}
+
+// unitAbbreviations single and two letter unit names. These are case
+// sensitive so that "M" (month) and "m" (minute) can both be used.
+var unitAbbreviations = map[string]Unit{
+	"ns": Nanosecond,
+	"us": Microsecond,
+	"µs": Microsecond,
+	"ms": Millisecond,
+	"s":  Second,
+	"m":  Minute,
+	"h":  Hour,
+	"H":  Hour,
+	"d":  Day,
+	"w":  Week,
+	"M":  Month,
+	"q":  Quarter,
+	"Q":  Quarter,
+	"y":  Year,
+}
+
+// unitWords full and short unit names, matched without regard to case.
+var unitWords = map[string]Unit{
+	"nanosecond": Nanosecond, "nanoseconds": Nanosecond, "nsec": Nanosecond, "nsecs": Nanosecond,
+	"microsecond": Microsecond, "microseconds": Microsecond, "usec": Microsecond, "usecs": Microsecond,
+	"millisecond": Millisecond, "milliseconds": Millisecond, "msec": Millisecond, "msecs": Millisecond,
+	"second": Second, "seconds": Second, "sec": Second, "secs": Second,
+	"minute": Minute, "minutes": Minute, "min": Minute, "mins": Minute,
+	"hour": Hour, "hours": Hour, "hr": Hour, "hrs": Hour,
+	"day": Day, "days": Day,
+	"week": Week, "weeks": Week, "wk": Week, "wks": Week,
+	"month": Month, "months": Month, "mon": Month, "mons": Month, "mo": Month, "mos": Month,
+	"quarter": Quarter, "quarters": Quarter, "qtr": Quarter, "qtrs": Quarter,
+	"year": Year, "years": Year, "yr": Year, "yrs": Year,
+}
+
+// ParseUnit get a unit from its name. Full names, plurals and common
+// abbreviations are accepted. Single letter abbreviations are case sensitive
+// with "M" being month and "m" being minute.
+func ParseUnit(name string) (unit Unit, ok bool) {
+	if unit, ok = unitAbbreviations[name]; ok {
+		return
+	}
+	unit, ok = unitWords[strings.ToLower(name)]
+
+	return
+}
+
+// daysIn get the number of days in a month for a year
+func daysIn(month time.Month, year int) int {
+	if month == time.February && isLeap(year) {
+		return 29
+	}
+	return int(utility.DaysBefore[month] - utility.DaysBefore[month-1])
+}
+
+// isLeap is the year a leap year in the proleptic Gregorian calendar
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// addMonthsClamped add months to a time keeping the wall clock and clamping
+// the day to the last day of the resulting month. Jan 31 plus one month is
+// the last day of February rather than a day in March.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	result, _ := addMonths(t, months, MonthClamp)
+	return result
+}
+
+// unitsDuration get n of an exact unit as a duration, with false when it
+// doesn't fit in a time.Duration
+func unitsDuration(n int, unit Unit) (time.Duration, bool) {
+	d, ok := overflow.Mul64(int64(n), int64(unit.Duration()))
+	return time.Duration(d), ok
+}
+
+// addUnits add n units to a time. Calendar units are applied to the date in
+// the time's location keeping the wall clock so that a day is not assumed to
+// be 24 hours long across daylight saving changes. Exact units are added as
+// durations.
+func addUnits(t time.Time, n int, unit Unit) time.Time {
+	switch unit {
+	case Day:
+		return t.AddDate(0, 0, n)
+	case Week:
+		return t.AddDate(0, 0, n*7)
+	case Month:
+		return addMonthsClamped(t, n)
+	case Quarter:
+		return addMonthsClamped(t, n*3)
+	case Year:
+		return addMonthsClamped(t, n*12)
+	}
+	return t.Add(time.Duration(n) * unit.Duration())
+}
+
+// startOfUnit get the first instant of the unit containing t in t's location.
+// Weeks start on Monday as in ISO-8601. Exact units are truncated by
+// subtracting the wall clock remainder so the result is correct during a
+// repeated hour. Calendar units start at midnight, or at the first instant of
+// the day when a daylight saving change skips midnight.
+func startOfUnit(t time.Time, unit Unit) time.Time {
+	switch unit {
+	case Nanosecond:
+		return t
+	case Microsecond, Millisecond, Second:
+		return t.Add(-time.Duration(t.Nanosecond()) % unit.Duration())
+	case Minute:
+		return t.Add(-time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case Hour:
+		return t.Add(-time.Duration(t.Minute())*time.Minute -
+			time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case Week:
+		return startOfWeek(t, time.Monday)
+	}
+
+	d := DateOf(t)
+	switch unit {
+	case Month:
+		d.Day = 1
+	case Quarter:
+		d.Month, d.Day = d.Month-(d.Month-1)%3, 1
+	case Year:
+		d.Month, d.Day = time.January, 1
+	}
+
+	return firstInstant(d, t.Location())
+}
+
+// startOfWeek get the first instant of the week containing t in t's location
+// for weeks starting on a given day
+func startOfWeek(t time.Time, first time.Weekday) time.Time {
+	d := DateOf(t)
+	return firstInstant(d.AddDays(-(int(t.Weekday())-int(first)+7)%7), t.Location())
+}
+
+// firstInstant get the first instant of a date in a location
+func firstInstant(d Date, location *time.Location) time.Time {
+	// DSTCompatible moves a skipped midnight to the end of the gap and never
+	// gives an error
+	start, _ := d.In(location, DSTCompatible)
+	return start
+}
+
+// nextStartOfUnit get the first instant of the unit after the one starting at
+// start
+func nextStartOfUnit(start time.Time, unit Unit) time.Time {
+	return shiftStartOfUnit(start, 1, unit)
+}
+
+// shiftStartOfUnit get the first instant of the unit n units after the one
+// starting at start, or before it for a negative n. Stepping the date rather
+// than the wall clock keeps a skipped midnight from pulling the result into
+// the wrong unit.
+func shiftStartOfUnit(start time.Time, n int, unit Unit) time.Time {
+	if !unit.IsCalendar() {
+		return start.Add(time.Duration(n) * unit.Duration())
+	}
+	d := DateOf(start)
+	switch unit {
+	case Day:
+		d = d.AddDays(n)
+	case Week:
+		d = d.AddDays(7 * n)
+	case Month:
+		d = d.AddMonths(n)
+	case Quarter:
+		d = d.AddMonths(3 * n)
+	case Year:
+		d = d.AddMonths(12 * n)
+	}
+	return firstInstant(d, start.Location())
+}
+
+// endOfUnit get the last instant of the unit containing t in t's location
+func endOfUnit(t time.Time, unit Unit) time.Time {
+	return nextStartOfUnit(startOfUnit(t, unit), unit).Add(-time.Nanosecond)
+}
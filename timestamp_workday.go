@@ -0,0 +1,196 @@
+package timestamp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// searchDays how far calendars and working hours search for a business day
+// or working time before giving up, which only happens for a schedule that
+// is almost always closed
+const searchDays = 366
+
+// Holidays a source of holidays for a WorkCalendar. HolidaySet is a fixed set
+// of dates and HolidayRules finds them for any year; any other rule for
+// deciding holidays can be plugged in.
+type Holidays interface {
+	IsHoliday(d Date) bool
+}
+
+// HolidaySet a fixed set of holiday dates mapped to their names
+type HolidaySet map[Date]string
+
+// IsHoliday is the date in the set
+func (s HolidaySet) IsHoliday(d Date) bool {
+	_, ok := s[d]
+	return ok
+}
+
+// HolidayFunc a function used as a source of holidays
+type HolidayFunc func(d Date) bool
+
+// IsHoliday call the function
+func (f HolidayFunc) IsHoliday(d Date) bool {
+	return f(d)
+}
+
+// WorkCalendar decides which days are business days in a location. A day is a
+// business day when it is not a weekend day and no holiday source lists it.
+// Times are placed on the calendar by their date in the calendar's location
+// and all stepping is done on dates, so days across a daylight saving change
+// or with no midnight are counted once like any other. A WorkCalendar is safe
+// for concurrent use as long as its holiday sources are.
+type WorkCalendar struct {
+	location *time.Location
+	weekend  [7]bool
+	holidays []Holidays
+}
+
+// NewWorkCalendar make a work calendar for a location. A nil weekend uses
+// Saturday and Sunday; an empty one makes every day other than holidays a
+// business day. Weekdays outside Sunday to Saturday wrap around the week. A
+// nil location uses UTC.
+func NewWorkCalendar(location *time.Location, weekend []time.Weekday, holidays ...Holidays) *WorkCalendar {
+	if location == nil {
+		location = time.UTC
+	}
+	if weekend == nil {
+		weekend = []time.Weekday{time.Saturday, time.Sunday}
+	}
+
+	c := &WorkCalendar{location: location, holidays: holidays}
+	for _, day := range weekend {
+		c.weekend[(day%7+7)%7] = true
+	}
+
+	return c
+}
+
+// Location get the location of the calendar
+func (c *WorkCalendar) Location() *time.Location {
+	return c.location
+}
+
+// IsBusinessDate is the date a business day
+func (c *WorkCalendar) IsBusinessDate(d Date) bool {
+	if c.weekend[d.Weekday()] {
+		return false
+	}
+	for _, h := range c.holidays {
+		if h.IsHoliday(d) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsBusinessDay is the date of t in the calendar's location a business day
+func (c *WorkCalendar) IsBusinessDay(t time.Time) bool {
+	return c.IsBusinessDate(DateOf(t.In(c.location)))
+}
+
+// workCalendarError make an error for a calendar with no business days
+func workCalendarError(caller string, d Date) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S(caller).S(": no business day within ").D(searchDays).S(" days of ").S(d.String())
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// AddBusinessDates move a date by n business days, back for a negative n. A
+// date that is not a business day counts from where it is, so one business
+// day after a Saturday is the Monday. An error is returned when no business
+// day is found within a year.
+func (c *WorkCalendar) AddBusinessDates(d Date, n int) (Date, error) {
+	step := 1
+	if n < 0 {
+		n, step = -n, -1
+	}
+	for sinceBusiness := 0; n > 0; sinceBusiness++ {
+		if sinceBusiness >= searchDays {
+			return Date{}, workCalendarError("timestamp.WorkCalendar.AddBusinessDates", d)
+		}
+		d = d.AddDays(step)
+		if c.IsBusinessDate(d) {
+			n, sinceBusiness = n-1, 0
+		}
+	}
+	return d, nil
+}
+
+// AddBusinessDays get the time n business days after t, or before t for a
+// negative n, keeping the wall clock of t in the calendar's location. A wall
+// clock skipped by a daylight saving change moves forward past the gap. With
+// n of 0 t is returned in the calendar's location. An error is returned when
+// no business day is found within a year.
+func (c *WorkCalendar) AddBusinessDays(t time.Time, n int) (time.Time, error) {
+	t = t.In(c.location)
+	if n == 0 {
+		return t, nil
+	}
+	d, err := c.AddBusinessDates(DateOf(t), n)
+	if err != nil {
+		return time.Time{}, err
+	}
+	// DSTCompatible never gives an error
+	result, _ := d.At(TimeOfDayOf(t)).In(c.location, DSTCompatible)
+
+	return result, nil
+}
+
+// NextBusinessDay get t if it falls on a business day, otherwise the first
+// instant of the next business day, in the calendar's location. An error is
+// returned when no business day is found within a year.
+func (c *WorkCalendar) NextBusinessDay(t time.Time) (time.Time, error) {
+	t = t.In(c.location)
+	if c.IsBusinessDay(t) {
+		return t, nil
+	}
+	d, err := c.AddBusinessDates(DateOf(t), 1)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return firstInstant(d, c.location), nil
+}
+
+// BusinessDaysBetween count the business days from the date of start up to
+// but not including the date of end in the calendar's location. When end is
+// before start the days from end up to start are counted and negated, so
+// AddBusinessDays(start, n) lands on the date of end when both are business
+// days.
+func (c *WorkCalendar) BusinessDaysBetween(start, end time.Time) int {
+	from, to := DateOf(start.In(c.location)), DateOf(end.In(c.location))
+	sign := 1
+	if to.Before(from) {
+		from, to, sign = to, from, -1
+	}
+
+	count := 0
+	for d := from; d.Before(to); d = d.AddDays(1) {
+		if c.IsBusinessDate(d) {
+			count++
+		}
+	}
+	return sign * count
+}
+
+// RangeOverBusinessDays returns a function over the business days from the
+// date of start to the date of end inclusive in the calendar's location, in
+// the manner of RangeOverTimes. Each call gives the first instant of the next
+// business day, which is midnight unless a daylight saving change skips it,
+// and a zero time once the range is done.
+func (c *WorkCalendar) RangeOverBusinessDays(start, end time.Time) func() (time time.Time, err error) {
+	next := RangeOverDays(start.In(c.location), end, true)
+
+	return func() (time.Time, error) {
+		for {
+			t, err := next()
+			if err != nil || t.IsZero() || c.IsBusinessDay(t) {
+				return t, err
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestWorkCalendar(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	holidays := timestamp.HolidaySet{
+		{Year: 2024, Month: time.March, Day: 29}: "Good Friday",
+		{Year: 2024, Month: time.April, Day: 1}:  "Easter Monday",
+	}
+	c := timestamp.NewWorkCalendar(toronto, nil, holidays)
+
+	thursday := time.Date(2024, 3, 28, 16, 30, 0, 0, toronto)
+	is.True(c.IsBusinessDay(thursday))
+	is.True(!c.IsBusinessDay(thursday.AddDate(0, 0, 1)))                   // Good Friday
+	is.True(!c.IsBusinessDay(thursday.AddDate(0, 0, 2)))                   // Saturday
+	is.True(c.IsBusinessDay(time.Date(2024, 3, 29, 3, 0, 0, 0, time.UTC))) // Thursday in Toronto
+
+	tests := []struct {
+		t        time.Time
+		n        int
+		expected string
+	}{
+		{thursday, 1, "2024-04-02T16:30:00-04:00"},
+		{thursday, 3, "2024-04-04T16:30:00-04:00"},
+		{thursday, -1, "2024-03-27T16:30:00-04:00"},
+		{thursday, 0, "2024-03-28T16:30:00-04:00"},
+		// Saturday counts from where it is
+		{time.Date(2024, 3, 30, 9, 0, 0, 0, toronto), 1, "2024-04-02T09:00:00-04:00"},
+		// Across the spring change the wall clock is kept
+		{time.Date(2024, 3, 8, 9, 0, 0, 0, toronto), 1, "2024-03-11T09:00:00-04:00"},
+		// A wall clock in the gap moves past it
+		{time.Date(2024, 3, 9, 2, 30, 0, 0, toronto).Add(24 * time.Hour), -1, "2024-03-08T03:30:00-05:00"},
+	}
+
+	for _, test := range tests {
+		got, err := c.AddBusinessDays(test.t, test.n)
+		is.NoErr(err)
+		is.Equal(got.Format(time.RFC3339), test.expected) // Time should match
+	}
+
+	next, err := c.NextBusinessDay(thursday)
+	is.NoErr(err)
+	is.Equal(next.Format(time.RFC3339), "2024-03-28T16:30:00-04:00")
+	next, err = c.NextBusinessDay(thursday.AddDate(0, 0, 1))
+	is.NoErr(err)
+	is.Equal(next.Format(time.RFC3339), "2024-04-02T00:00:00-04:00")
+
+	is.Equal(c.BusinessDaysBetween(thursday, thursday.AddDate(0, 0, 6)), 2)
+	is.Equal(c.BusinessDaysBetween(thursday.AddDate(0, 0, 6), thursday), -2)
+	is.Equal(c.BusinessDaysBetween(thursday, thursday), 0)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, toronto)
+	is.Equal(c.BusinessDaysBetween(start, start.AddDate(1, 0, 0)), 262-2)
+	got, err := c.AddBusinessDays(start, c.BusinessDaysBetween(start, thursday))
+	is.NoErr(err)
+	is.Equal(got, start.AddDate(0, 0, 87))
+
+	// A calendar with a Friday and Saturday weekend and a rule for holidays
+	dubai, err := time.LoadLocation("Asia/Dubai")
+	is.NoErr(err)
+	firstOfMonth := timestamp.HolidayFunc(func(d timestamp.Date) bool { return d.Day == 1 })
+	c = timestamp.NewWorkCalendar(dubai, []time.Weekday{time.Friday, time.Saturday}, firstOfMonth)
+	sunday := time.Date(2024, 3, 31, 9, 0, 0, 0, dubai)
+	is.True(c.IsBusinessDay(sunday))
+	got, err = c.AddBusinessDays(sunday, 1)
+	is.NoErr(err)
+	is.Equal(got.Format("2006-01-02"), "2024-04-02")
+	got, err = c.AddBusinessDays(sunday, -1)
+	is.NoErr(err)
+	is.Equal(got.Format("2006-01-02"), "2024-03-28")
+
+	// Weekdays outside the week wrap around it
+	c = timestamp.NewWorkCalendar(dubai, []time.Weekday{-1, 12})
+	is.True(!c.IsBusinessDay(time.Date(2024, 3, 29, 9, 0, 0, 0, dubai))) // Friday
+	is.True(!c.IsBusinessDay(time.Date(2024, 3, 30, 9, 0, 0, 0, dubai))) // Saturday
+	is.True(c.IsBusinessDay(sunday))
+}
+
+func TestWorkCalendarNoBusinessDays(t *testing.T) {
+	is := is.New(t)
+
+	monday := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	always := timestamp.HolidayFunc(func(d timestamp.Date) bool { return true })
+	calendars := []*timestamp.WorkCalendar{
+		timestamp.NewWorkCalendar(time.UTC, []time.Weekday{
+			time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday,
+		}),
+		timestamp.NewWorkCalendar(time.UTC, nil, always),
+	}
+	for _, c := range calendars {
+		_, err := c.AddBusinessDays(monday, 1)
+		t.Log(err)
+		is.True(err != nil) // No business day should be an error
+		_, err = c.AddBusinessDays(monday, -1)
+		is.True(err != nil) // No business day should be an error
+		_, err = c.NextBusinessDay(monday)
+		is.True(err != nil) // No business day should be an error
+	}
+}
+
+func TestRangeOverBusinessDays(t *testing.T) {
+	is := is.New(t)
+
+	santiago, err := time.LoadLocation("America/Santiago")
+	is.NoErr(err)
+
+	c := timestamp.NewWorkCalendar(santiago, []time.Weekday{time.Saturday})
+
+	// Midnight is skipped on Sunday 2022-09-11
+	start := time.Date(2022, 9, 8, 12, 0, 0, 0, santiago)
+	end := time.Date(2022, 9, 12, 12, 0, 0, 0, santiago)
+	next := c.RangeOverBusinessDays(start, end)
+
+	var got []string
+	for {
+		d, err := next()
+		is.NoErr(err)
+		if d.IsZero() {
+			break
+		}
+		got = append(got, d.Format(time.RFC3339))
+	}
+	is.Equal(got, []string{
+		"2022-09-08T00:00:00-04:00",
+		"2022-09-09T00:00:00-04:00",
+		"2022-09-11T01:00:00-03:00",
+		"2022-09-12T00:00:00-03:00",
+	})
+}
@@ -0,0 +1,248 @@
+package timestamp
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/imarsman/timestamp/pkg/xfmt"
+)
+
+// Shift a span of working time in a day. A shift whose End is not after its
+// Start runs overnight and ends on the following day, so 22:00-06:00 is a
+// night shift and 00:00-00:00 is a whole day.
+type Shift struct {
+	Start TimeOfDay
+	End   TimeOfDay
+}
+
+// String get the shift in the form ParseShifts reads, such as 09:00:00-17:30:00
+func (s Shift) String() string {
+	return s.Start.String() + "-" + s.End.String()
+}
+
+// ParseShifts parse a comma separated list of shifts such as
+// 09:00-12:00, 13:00-17:30 for a day with a lunch break or 22:00-06:00 for a
+// night shift. The times are read with ParseTimeOfDay.
+func ParseShifts(value string) (shifts []Shift, err error) {
+	for _, part := range strings.Split(value, ",") {
+		start, end, found := strings.Cut(strings.TrimSpace(part), "-")
+		if !found {
+			return nil, civilError("timestamp.ParseShifts", "shift needs a start and end '"+part+"'", value)
+		}
+		var s Shift
+		if s.Start, err = ParseTimeOfDay(strings.TrimSpace(start)); err != nil {
+			return nil, civilError("timestamp.ParseShifts", "invalid start '"+start+"'", value)
+		}
+		if s.End, err = ParseTimeOfDay(strings.TrimSpace(end)); err != nil {
+			return nil, civilError("timestamp.ParseShifts", "invalid end '"+end+"'", value)
+		}
+		shifts = append(shifts, s)
+	}
+
+	return shifts, nil
+}
+
+// WorkingHours a weekly schedule of working time in a location, such as
+// Monday to Friday 09:00-17:30 with a lunch break. Shifts belong to the day
+// they start on and are skipped when that day is a holiday. Shifts are placed
+// on the wall clock of each day, so they keep their hours across a daylight
+// saving change and a shift spanning the change is an hour longer or shorter.
+// A shift edge in a skipped hour moves forward past the gap. Overlapping
+// shifts count once.
+type WorkingHours struct {
+	location *time.Location
+	shifts   [7][]Shift
+	holidays []Holidays
+}
+
+// NewWorkingHours make working hours for a location from the shifts worked on
+// each weekday. Weekdays outside Sunday to Saturday wrap around the week. A
+// nil location uses UTC.
+func NewWorkingHours(location *time.Location, week map[time.Weekday][]Shift, holidays ...Holidays) *WorkingHours {
+	if location == nil {
+		location = time.UTC
+	}
+
+	w := &WorkingHours{location: location, holidays: holidays}
+	for day, shifts := range week {
+		day = (day%7 + 7) % 7
+		w.shifts[day] = append(w.shifts[day], shifts...)
+	}
+
+	return w
+}
+
+// Location get the location of the working hours
+func (w *WorkingHours) Location() *time.Location {
+	return w.location
+}
+
+// isHoliday is the date a holiday
+func (w *WorkingHours) isHoliday(d Date) bool {
+	for _, h := range w.holidays {
+		if h.IsHoliday(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// span get the working time of the shifts starting on the days from first,
+// along with those starting the day before first that may run into it
+func (w *WorkingHours) span(first Date, days int) IntervalSet {
+	var intervals []Interval
+	for d := first.AddDays(-1); !d.After(first.AddDays(days - 1)); d = d.AddDays(1) {
+		if w.isHoliday(d) {
+			continue
+		}
+		for _, s := range w.shifts[d.Weekday()] {
+			endDate := d
+			if s.End.Compare(s.Start) <= 0 {
+				endDate = d.AddDays(1)
+			}
+			// DSTCompatible never gives an error
+			start, _ := d.At(s.Start).In(w.location, DSTCompatible)
+			end, _ := endDate.At(s.End).In(w.location, DSTCompatible)
+			intervals = append(intervals, Interval{Start: start, End: end})
+		}
+	}
+
+	return NewIntervalSet(intervals...)
+}
+
+// workingHoursError make an error for working hours with no working time
+func workingHoursError(caller string, t time.Time) error {
+	// Avoid allocations that would occur with fmt.Sprintf
+	xfmtBuf := new(xfmt.Buffer)
+	xfmtBuf.S(caller).S(": no working time within ").D(searchDays).S(" days of ").S(t.Format(time.RFC3339Nano))
+
+	return errors.New(BytesToString(xfmtBuf.Bytes()...))
+}
+
+// Add get the instant when a working duration from t has been worked, or for
+// a negative duration the instant it would have had to start. Time outside
+// working hours does not count, so 2 hours from 16:30 with a 17:30 close and
+// a 09:00 open is 10:00 the next working day. Work that finishes exactly at a
+// close gives the close. The result is in the location of the working hours.
+// An error is returned when no working time is found within a year.
+func (w *WorkingHours) Add(t time.Time, d time.Duration) (time.Time, error) {
+	t = t.In(w.location)
+	if d == 0 {
+		return t, nil
+	}
+
+	// Walk a week at a time, with cursor marking where the working time
+	// counted so far ends
+	cursor, remaining := t, d
+	day := DateOf(t)
+	if remaining < 0 {
+		day = day.AddDays(-6)
+	}
+	for sinceWork := 0; sinceWork < searchDays; sinceWork += 7 {
+		intervals := w.span(day, 7).intervals
+		if remaining > 0 {
+			for _, i := range intervals {
+				if !i.End.After(cursor) {
+					continue
+				}
+				start := laterOf(i.Start, cursor)
+				available := i.End.Sub(start)
+				if remaining <= available {
+					return start.Add(remaining), nil
+				}
+				remaining -= available
+				cursor, sinceWork = i.End, 0
+			}
+			day = day.AddDays(7)
+			continue
+		}
+		for n := len(intervals) - 1; n >= 0; n-- {
+			i := intervals[n]
+			if !i.Start.Before(cursor) {
+				continue
+			}
+			end := earlierOf(i.End, cursor)
+			available := end.Sub(i.Start)
+			if -remaining <= available {
+				return end.Add(remaining), nil
+			}
+			remaining += available
+			cursor, sinceWork = i.Start, 0
+		}
+		day = day.AddDays(-7)
+	}
+
+	return time.Time{}, workingHoursError("timestamp.WorkingHours.Add", t)
+}
+
+// Elapsed get the working time from start to end, negative if end is before
+// start
+func (w *WorkingHours) Elapsed(start, end time.Time) time.Duration {
+	if end.Before(start) {
+		return -w.Elapsed(end, start)
+	}
+	first, last := DateOf(start.In(w.location)), DateOf(end.In(w.location))
+	set := w.span(first, last.DaysSince(first)+1)
+
+	return set.Intersect(NewIntervalSet(Interval{Start: start, End: end})).Duration()
+}
+
+// IsOpen is t within working hours
+func (w *WorkingHours) IsOpen(t time.Time) bool {
+	return w.span(DateOf(t.In(w.location)), 1).Contains(t)
+}
+
+// NextOpen get the first instant at or after t that is within working hours,
+// which is t if it is already open. False is returned when there is no
+// working time within a year.
+func (w *WorkingHours) NextOpen(t time.Time) (time.Time, bool) {
+	t = t.In(w.location)
+	day := DateOf(t)
+	for searched := 0; searched < searchDays; searched += 7 {
+		for _, i := range w.span(day, 7).intervals {
+			if i.End.After(t) {
+				return laterOf(i.Start, t), true
+			}
+		}
+		day = day.AddDays(7)
+	}
+	return time.Time{}, false
+}
+
+// NextClose get the first instant after t at which working hours close. For a
+// t outside working hours this is the close of the next working time. False
+// is returned when there is no working time within a year, or no close.
+func (w *WorkingHours) NextClose(t time.Time) (time.Time, bool) {
+	open, ok := w.NextOpen(t)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	// Working time that carries on past the days looked at, such as a shift
+	// running into the next week, is followed until it ends
+	limit := open.AddDate(0, 0, searchDays)
+	for open.Before(limit) {
+		for _, i := range w.span(DateOf(open), 7).intervals {
+			if i.Contains(open) {
+				open = i.End
+				break
+			}
+		}
+		if !w.IsOpen(open) {
+			return open, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// NextTransition get the next instant after t at which working hours open or
+// close, and whether they open then
+func (w *WorkingHours) NextTransition(t time.Time) (next time.Time, opens bool, ok bool) {
+	if w.IsOpen(t) {
+		next, ok = w.NextClose(t)
+		return next, false, ok
+	}
+	next, ok = w.NextOpen(t)
+	return next, true, ok
+}
@@ -0,0 +1,142 @@
+package timestamp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/timestamp"
+	"github.com/matryer/is"
+)
+
+func TestWorkingHours(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	day, err := timestamp.ParseShifts("09:00-12:00, 13:00-17:30")
+	is.NoErr(err)
+	week := map[time.Weekday][]timestamp.Shift{
+		time.Monday: day, time.Tuesday: day, time.Wednesday: day, time.Thursday: day, time.Friday: day,
+	}
+	goodFriday := timestamp.HolidaySet{{Year: 2024, Month: time.March, Day: 29}: "Good Friday"}
+	w := timestamp.NewWorkingHours(toronto, week, goodFriday)
+
+	thursday := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 28, hour, minute, 0, 0, toronto)
+	}
+
+	tests := []struct {
+		t        time.Time
+		d        time.Duration
+		expected string
+	}{
+		{thursday(16, 30), 2 * time.Hour, "2024-04-01T10:00:00-04:00"},
+		{thursday(11, 30), time.Hour, "2024-03-28T13:30:00-04:00"},
+		{thursday(16, 30), time.Hour, "2024-03-28T17:30:00-04:00"},
+		{thursday(20, 0), 0, "2024-03-28T20:00:00-04:00"},
+		{thursday(20, 0), time.Minute, "2024-04-01T09:01:00-04:00"},
+		{thursday(7, 0), 8 * time.Hour, "2024-04-01T09:30:00-04:00"},
+		{time.Date(2024, 4, 1, 10, 0, 0, 0, toronto), -2 * time.Hour, "2024-03-28T16:30:00-04:00"},
+		{thursday(13, 0), -time.Hour, "2024-03-28T11:00:00-04:00"},
+		// A working week
+		{time.Date(2024, 4, 1, 9, 0, 0, 0, toronto), 37*time.Hour + 30*time.Minute, "2024-04-05T17:30:00-04:00"},
+		// Across the spring change the hours stay on the wall clock
+		{time.Date(2024, 3, 8, 22, 0, 0, 0, time.UTC), time.Hour, "2024-03-11T09:30:00-04:00"},
+	}
+
+	for _, test := range tests {
+		got, err := w.Add(test.t, test.d)
+		is.NoErr(err)                                     // Should add without error
+		is.Equal(got.Format(time.RFC3339), test.expected) // Time should match
+	}
+
+	is.Equal(w.Elapsed(thursday(16, 30), time.Date(2024, 4, 1, 10, 0, 0, 0, toronto)), 2*time.Hour)
+	is.Equal(w.Elapsed(time.Date(2024, 4, 1, 10, 0, 0, 0, toronto), thursday(16, 30)), -2*time.Hour)
+	is.Equal(w.Elapsed(thursday(0, 0), thursday(23, 0)), 7*time.Hour+30*time.Minute)
+
+	is.True(w.IsOpen(thursday(9, 0)))
+	is.True(!w.IsOpen(thursday(12, 30)))
+	is.True(!w.IsOpen(thursday(17, 30)))
+
+	next, ok := w.NextOpen(thursday(12, 30))
+	is.True(ok)
+	is.Equal(next.Format(time.RFC3339), "2024-03-28T13:00:00-04:00")
+	next, ok = w.NextOpen(thursday(17, 45))
+	is.True(ok)
+	is.Equal(next.Format(time.RFC3339), "2024-04-01T09:00:00-04:00")
+	next, ok = w.NextClose(thursday(12, 30))
+	is.True(ok)
+	is.Equal(next.Format(time.RFC3339), "2024-03-28T17:30:00-04:00")
+
+	next, opens, ok := w.NextTransition(thursday(10, 0))
+	is.True(ok)
+	is.True(!opens)
+	is.Equal(next.Format(time.RFC3339), "2024-03-28T12:00:00-04:00")
+	next, opens, ok = w.NextTransition(thursday(12, 0))
+	is.True(ok)
+	is.True(opens)
+	is.Equal(next.Format(time.RFC3339), "2024-03-28T13:00:00-04:00")
+
+	_, err = timestamp.NewWorkingHours(toronto, nil).Add(thursday(9, 0), time.Hour)
+	is.True(err != nil) // No working time
+	t.Log(err)
+
+	// Weekdays outside the week wrap around it
+	wrapped := timestamp.NewWorkingHours(toronto, map[time.Weekday][]timestamp.Shift{-3: {{Start: timestamp.TimeOfDay{Hour: 9}, End: timestamp.TimeOfDay{Hour: 17}}}})
+	is.True(wrapped.IsOpen(thursday(10, 0)))
+	is.True(!wrapped.IsOpen(thursday(10, 0).AddDate(0, 0, 1)))
+
+	for _, input := range []string{"09:00", "09:00-25:00", "x-17:00", ""} {
+		_, err := timestamp.ParseShifts(input)
+		is.True(err != nil) // Should fail
+		t.Log(err)
+	}
+}
+
+func TestWorkingHoursOvernight(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	night, err := timestamp.ParseShifts("22:00-06:00")
+	is.NoErr(err)
+	week := map[time.Weekday][]timestamp.Shift{}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		week[day] = night
+	}
+	w := timestamp.NewWorkingHours(toronto, week)
+
+	// The night shifts over the daylight saving changes are 7 and 9 hours
+	is.Equal(w.Elapsed(time.Date(2024, 3, 9, 20, 0, 0, 0, toronto), time.Date(2024, 3, 10, 8, 0, 0, 0, toronto)), 7*time.Hour)
+	is.Equal(w.Elapsed(time.Date(2024, 11, 2, 20, 0, 0, 0, toronto), time.Date(2024, 11, 3, 8, 0, 0, 0, toronto)), 9*time.Hour)
+
+	got, err := w.Add(time.Date(2024, 3, 9, 23, 0, 0, 0, toronto), 6*time.Hour)
+	is.NoErr(err)
+	is.Equal(got.Format(time.RFC3339), "2024-03-10T06:00:00-04:00")
+	got, err = w.Add(time.Date(2024, 3, 10, 6, 0, 0, 0, toronto), -7*time.Hour)
+	is.NoErr(err)
+	is.Equal(got.Format(time.RFC3339), "2024-03-09T22:00:00-05:00")
+
+	is.True(w.IsOpen(time.Date(2024, 5, 1, 3, 0, 0, 0, toronto)))
+	next, ok := w.NextClose(time.Date(2024, 5, 1, 3, 0, 0, 0, toronto))
+	is.True(ok)
+	is.Equal(next.Format(time.RFC3339), "2024-05-01T06:00:00-04:00")
+
+	// A shift starting in the skipped hour starts after the gap
+	gap := timestamp.NewWorkingHours(toronto, map[time.Weekday][]timestamp.Shift{
+		time.Sunday: {{Start: timestamp.TimeOfDay{Hour: 2, Minute: 30}, End: timestamp.TimeOfDay{Hour: 4}}},
+	})
+	is.Equal(gap.Elapsed(time.Date(2024, 3, 10, 0, 0, 0, 0, toronto), time.Date(2024, 3, 11, 0, 0, 0, 0, toronto)), 30*time.Minute)
+
+	// Always open never closes
+	whole := []timestamp.Shift{{}}
+	always := timestamp.NewWorkingHours(toronto, map[time.Weekday][]timestamp.Shift{
+		time.Sunday: whole, time.Monday: whole, time.Tuesday: whole, time.Wednesday: whole,
+		time.Thursday: whole, time.Friday: whole, time.Saturday: whole,
+	})
+	is.True(always.IsOpen(time.Date(2024, 5, 1, 3, 0, 0, 0, toronto)))
+	_, ok = always.NextClose(time.Date(2024, 5, 1, 3, 0, 0, 0, toronto))
+	is.True(!ok) // Should never close
+}